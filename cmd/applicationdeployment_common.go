@@ -12,10 +12,12 @@ import (
 var (
 	flagAuroraConfig string
 	flagOverrides    []string
+	flagSets         []string
 	flagNoPrompt     bool
 	flagVersion      string
 	flagCluster      string
 	flagExcludes     []string
+	flagSelector     string
 )
 
 type DeploymentInfo struct {
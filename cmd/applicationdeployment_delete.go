@@ -9,7 +9,9 @@ import (
 	"github.com/pkg/errors"
 	"github.com/skatteetaten/ao/pkg/client"
 	"github.com/skatteetaten/ao/pkg/config"
+	"github.com/skatteetaten/ao/pkg/deploymentspec"
 	"github.com/skatteetaten/ao/pkg/prompt"
+	"github.com/skatteetaten/ao/pkg/report"
 	"github.com/skatteetaten/ao/pkg/service"
 	"github.com/spf13/cobra"
 )
@@ -23,7 +25,7 @@ var (
 	}
 
 	applicationDeploymentDeleteCmd = &cobra.Command{
-		Use:   "delete <applicationDeploymentRef>",
+		Use:   "delete [applicationDeploymentRef]",
 		Short: "Delete application deployment with the given reference",
 		RunE:  deleteApplicationDeployment,
 	}
@@ -63,6 +65,8 @@ func init() {
 	applicationDeploymentDeleteCmd.Flags().StringVarP(&flagCluster, "cluster", "c", "", "Limit deletion to given cluster name")
 	applicationDeploymentDeleteCmd.Flags().BoolVarP(&flagNoPrompt, "no-prompt", "", false, "Suppress prompts")
 	applicationDeploymentDeleteCmd.Flags().StringArrayVarP(&flagExcludes, "exclude", "e", []string{}, "Select applications or environments to exclude from deletion")
+	applicationDeploymentDeleteCmd.Flags().StringArrayVarP(&flagReports, "report", "", []string{}, reportUsage)
+	applicationDeploymentDeleteCmd.Flags().StringVarP(&flagSelector, "selector", "s", "", "Select applications by deployment spec fields, e.g. 'type=deploy,cluster in (utv,test),!pause'")
 
 	applicationDeploymentDeleteCmd.Flags().BoolVarP(&flagNoPrompt, "force", "f", false, "Suppress prompts")
 	applicationDeploymentDeleteCmd.Flags().MarkHidden("force")
@@ -72,7 +76,7 @@ func init() {
 
 func deleteApplicationDeployment(cmd *cobra.Command, args []string) error {
 
-	if len(args) > 2 || len(args) < 1 {
+	if len(args) > 2 || (len(args) < 1 && flagSelector == "") {
 		return cmd.Usage()
 	}
 
@@ -81,11 +85,18 @@ func deleteApplicationDeployment(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	search := args[0]
-	if len(args) == 2 {
-		search = fmt.Sprintf("%s/%s", args[0], args[1])
+	selector, err := deploymentspec.ParseSelector(flagSelector)
+	if err != nil {
+		return err
 	}
 
+	reportTargets, err := report.ParseTargets(flagReports)
+	if err != nil {
+		return err
+	}
+
+	search := strings.Join(args, "/")
+
 	auroraConfigName := AO.Affiliation
 	if flagAuroraConfig != "" {
 		auroraConfigName = flagAuroraConfig
@@ -108,6 +119,11 @@ func deleteApplicationDeployment(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	filteredDeploymentSpecs = selector.Filter(filteredDeploymentSpecs)
+	if len(filteredDeploymentSpecs) == 0 {
+		return errors.New("No applications to delete")
+	}
+
 	deployInfos, err := getDeployedApplications(getApplicationDeploymentClient, filteredDeploymentSpecs, auroraConfigName, pFlagToken)
 	if err != nil {
 		return err
@@ -129,8 +145,15 @@ func deleteApplicationDeployment(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	recordDeleteResults(auroraConfigName, apiClient.RefName, fullResults, cmd.ErrOrStderr())
+	notifyDeleteResults(apiClient, auroraConfigName, apiClient.RefName, fullResults, cmd.ErrOrStderr())
+
 	printFullResults(fullResults, cmd.OutOrStdout())
 
+	if err := report.Write(reportTargets, newDeleteReport(auroraConfigName, fullResults)); err != nil {
+		return err
+	}
+
 	for _, result := range fullResults {
 		if !result.deleteResults.Success {
 			return errors.New("One or more delete operations failed")
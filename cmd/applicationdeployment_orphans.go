@@ -0,0 +1,216 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/skatteetaten/ao/pkg/client"
+	"github.com/skatteetaten/ao/pkg/prompt"
+	"github.com/spf13/cobra"
+)
+
+const exampleApplicationDeploymentOrphans = `  # List application deployments on reachable clusters without an application file in the AuroraConfig
+  ao ad orphans
+
+  # List orphans in the foo environment, you are asked whether they should be deleted
+  ao ad orphans foo
+
+  # Delete orphans in the foo environment from a script
+  ao ad orphans foo --delete --no-prompt`
+
+var flagDeleteOrphans bool
+
+var applicationDeploymentOrphansCmd = &cobra.Command{
+	Use:     "orphans [environment]",
+	Short:   "List application deployments that no longer have an application file in the AuroraConfig",
+	Example: exampleApplicationDeploymentOrphans,
+	RunE:    applicationDeploymentOrphans,
+}
+
+type orphan struct {
+	DeploymentInfo
+	ApplicationDeploymentRef string
+}
+
+func init() {
+	applicationDeploymentCmd.AddCommand(applicationDeploymentOrphansCmd)
+	applicationDeploymentOrphansCmd.Flags().StringVarP(&flagCluster, "cluster", "c", "", "Limit search to given cluster name")
+	applicationDeploymentOrphansCmd.Flags().StringVarP(&flagAuroraConfig, "auroraconfig", "a", "", "Overrides the logged in AuroraConfig")
+	applicationDeploymentOrphansCmd.Flags().BoolVarP(&flagDeleteOrphans, "delete", "", false, "Delete the orphans without asking when combined with --no-prompt")
+	applicationDeploymentOrphansCmd.Flags().BoolVarP(&flagNoPrompt, "no-prompt", "", false, "Suppress prompts")
+}
+
+func applicationDeploymentOrphans(cmd *cobra.Command, args []string) error {
+	if len(args) > 1 {
+		return cmd.Usage()
+	}
+
+	if err := validateParams(); err != nil {
+		return err
+	}
+
+	environment := ""
+	if len(args) == 1 {
+		environment = args[0]
+	}
+
+	auroraConfigName := AO.Affiliation
+	if flagAuroraConfig != "" {
+		auroraConfigName = flagAuroraConfig
+	}
+
+	apiClient, err := getAPIClient(auroraConfigName, pFlagToken, flagCluster)
+	if err != nil {
+		return err
+	}
+
+	fileNames, err := apiClient.GetFileNames()
+	if err != nil {
+		return err
+	}
+
+	var partitions []Partition
+	for _, name := range sortedClusterNames() {
+		cluster := AO.Clusters[name]
+		if flagCluster != "" && name != flagCluster {
+			continue
+		}
+		if !cluster.Reachable {
+			cmd.Printf("Skipping %s, cluster is not reachable\n", name)
+			continue
+		}
+		partitions = append(partitions, Partition{Cluster: *cluster, AuroraConfigName: auroraConfigName, OverrideToken: pFlagToken})
+	}
+
+	orphans, err := findOrphans(getDeploymentStatusClient, partitions, fileNames.GetApplicationDeploymentRefs(), environment)
+	if err != nil {
+		return err
+	}
+
+	if len(orphans) == 0 {
+		cmd.Println("No orphaned application deployments found")
+		return nil
+	}
+
+	header, rows := getOrphanTable(orphans)
+	DefaultTablePrinter(header, rows, cmd.OutOrStdout())
+
+	shouldDelete := flagDeleteOrphans
+	if !flagNoPrompt {
+		message := fmt.Sprintf("Do you want to delete %d orphaned application deployment(s)?", len(orphans))
+		shouldDelete = prompt.Confirm(message, false)
+	}
+	if !shouldDelete {
+		return nil
+	}
+
+	var deployInfos []DeploymentInfo
+	for _, o := range orphans {
+		deployInfos = append(deployInfos, o.DeploymentInfo)
+	}
+
+	deletePartitions, err := createDeploymentPartitions(auroraConfigName, pFlagToken, AO.Clusters, deployInfos)
+	if err != nil {
+		return err
+	}
+
+	fullResults, err := deleteFromReachableClusters(getApplicationDeploymentClient, deletePartitions)
+	if err != nil {
+		return err
+	}
+
+	recordDeleteResults(auroraConfigName, apiClient.RefName, fullResults, cmd.ErrOrStderr())
+	notifyDeleteResults(apiClient, auroraConfigName, apiClient.RefName, fullResults, cmd.ErrOrStderr())
+
+	printFullResults(fullResults, cmd.OutOrStdout())
+
+	for _, result := range fullResults {
+		if !result.deleteResults.Success {
+			return errors.New("One or more delete operations failed")
+		}
+	}
+
+	return nil
+}
+
+// findOrphans lists the application deployments on every cluster concurrently and returns those
+// without a ref in the AuroraConfig. An empty environment matches all environments.
+func findOrphans(getStatusClient func(partition Partition) client.DeploymentStatusClient, partitions []Partition, refs []string, environment string) ([]orphan, error) {
+	known := make(map[string]bool)
+	for _, ref := range refs {
+		known[ref] = true
+	}
+
+	type partialOrphans struct {
+		orphans []orphan
+		err     error
+	}
+	results := make(chan partialOrphans)
+
+	for _, partition := range partitions {
+		go func(partition Partition) {
+			resources, err := getStatusClient(partition).GetApplicationDeployments(partition.AuroraConfigName)
+			if err != nil {
+				results <- partialOrphans{err: errors.Wrapf(err, "Could not list application deployments on %s", partition.Cluster.Name)}
+				return
+			}
+
+			var found []orphan
+			for _, resource := range resources {
+				if environment != "" && resource.Environment != environment && resource.Namespace != partition.AuroraConfigName+"-"+environment {
+					continue
+				}
+				if known[resource.ApplicationDeploymentRef()] {
+					continue
+				}
+				found = append(found, orphan{
+					DeploymentInfo:           *newDeploymentInfo(resource.Namespace, resource.Name, partition.Cluster.Name),
+					ApplicationDeploymentRef: resource.ApplicationDeploymentRef(),
+				})
+			}
+			results <- partialOrphans{orphans: found}
+		}(partition)
+	}
+
+	var orphans []orphan
+	var firstErr error
+	for i := 0; i < len(partitions); i++ {
+		result := <-results
+		if result.err != nil && firstErr == nil {
+			firstErr = result.err
+		}
+		orphans = append(orphans, result.orphans...)
+	}
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	sort.Slice(orphans, func(i, j int) bool {
+		if orphans[i].ClusterName != orphans[j].ClusterName {
+			return orphans[i].ClusterName < orphans[j].ClusterName
+		}
+		return orphans[i].ApplicationDeploymentRef < orphans[j].ApplicationDeploymentRef
+	})
+
+	return orphans, nil
+}
+
+func getOrphanTable(orphans []orphan) (string, []string) {
+	var rows []string
+	for _, o := range orphans {
+		rows = append(rows, fmt.Sprintf("%s\t%s\t%s\t%s", o.ClusterName, o.Namespace, o.Name, o.ApplicationDeploymentRef))
+	}
+
+	header := "CLUSTER\tNAMESPACE\tAPPLICATION\tAPPLICATION_DEPLOYMENT_REF"
+	return header, rows
+}
+
+func sortedClusterNames() []string {
+	var names []string
+	for name := range AO.Clusters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/skatteetaten/ao/pkg/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_findOrphans(t *testing.T) {
+	statusClientMock := client.NewDeploymentStatusClientMock()
+	statusClientMock.ApplicationDeployments = []client.ApplicationDeploymentResource{
+		{Namespace: "sales-dev", Name: "crm", Environment: "dev", Application: "crm"},
+		{Namespace: "sales-dev", Name: "legacy", Environment: "dev", Application: "legacy"},
+		{Namespace: "sales-test", Name: "erp", Environment: "test", Application: "erp"},
+	}
+	statusClientMock.On("GetApplicationDeployments", "sales")
+
+	getStatusClient := func(partition Partition) client.DeploymentStatusClient {
+		return statusClientMock
+	}
+
+	partitions := []Partition{
+		{Cluster: *newTestCluster("west", true), AuroraConfigName: "sales"},
+		{Cluster: *newTestCluster("east", true), AuroraConfigName: "sales"},
+	}
+
+	orphans, err := findOrphans(getStatusClient, partitions, []string{"dev/crm", "dev/erp"}, "")
+	assert.NoError(t, err)
+	assert.Len(t, orphans, 4)
+	assert.Equal(t, orphan{DeploymentInfo: *newDeploymentInfo("sales-dev", "legacy", "east"), ApplicationDeploymentRef: "dev/legacy"}, orphans[0])
+	assert.Equal(t, "test/erp", orphans[1].ApplicationDeploymentRef)
+	assert.Equal(t, "west", orphans[2].ClusterName)
+
+	orphans, err = findOrphans(getStatusClient, partitions[:1], []string{"dev/crm"}, "dev")
+	assert.NoError(t, err)
+	assert.Len(t, orphans, 1)
+	assert.Equal(t, "dev/legacy", orphans[0].ApplicationDeploymentRef)
+
+	statusClientMock.AssertNumberOfCalls(t, "GetApplicationDeployments", 3)
+	statusClientMock.AssertCalled(t, "GetApplicationDeployments", mock.Anything)
+
+	header, rows := getOrphanTable(orphans)
+	assert.Equal(t, "CLUSTER\tNAMESPACE\tAPPLICATION\tAPPLICATION_DEPLOYMENT_REF", header)
+	assert.Equal(t, []string{"west\tsales-dev\tlegacy\tdev/legacy"}, rows)
+}
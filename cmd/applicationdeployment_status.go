@@ -0,0 +1,278 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/skatteetaten/ao/pkg/client"
+	"github.com/skatteetaten/ao/pkg/deploymentspec"
+	"github.com/skatteetaten/ao/pkg/service"
+	"github.com/spf13/cobra"
+)
+
+const exampleApplicationDeploymentStatus = `  # Show the state of all applications in the foo environment on every cluster
+  ao ad status foo
+
+  # Show the state of bar across all environments on the utv cluster
+  ao ad status bar -c utv`
+
+var applicationDeploymentStatusCmd = &cobra.Command{
+	Use:     "status [applicationDeploymentRef]",
+	Short:   "Show deployed version, pause and replicas and highlight drift from the AuroraConfig",
+	Example: exampleApplicationDeploymentStatus,
+	RunE:    applicationDeploymentStatus,
+}
+
+// applicationStatus is the deployed state of a deployment spec on a cluster
+type applicationStatus struct {
+	Spec       deploymentspec.DeploymentSpec
+	Cluster    string
+	Namespace  string
+	Deployment client.DeploymentStatus
+	Error      string
+}
+
+func init() {
+	applicationDeploymentCmd.AddCommand(applicationDeploymentStatusCmd)
+	applicationDeploymentStatusCmd.Flags().StringVarP(&flagCluster, "cluster", "c", "", "Limit status to given cluster name")
+	applicationDeploymentStatusCmd.Flags().StringArrayVarP(&flagExcludes, "exclude", "e", []string{}, "Select applications or environments to exclude")
+	applicationDeploymentStatusCmd.Flags().StringVarP(&flagSelector, "selector", "s", "", "Select applications by deployment spec fields, e.g. 'type=deploy,!pause'")
+	applicationDeploymentStatusCmd.Flags().StringVarP(&flagAuroraConfig, "auroraconfig", "a", "", "Overrides the logged in AuroraConfig")
+}
+
+func applicationDeploymentStatus(cmd *cobra.Command, args []string) error {
+	if len(args) > 2 || (len(args) < 1 && flagSelector == "") {
+		return cmd.Usage()
+	}
+
+	if err := validateParams(); err != nil {
+		return err
+	}
+
+	selector, err := deploymentspec.ParseSelector(flagSelector)
+	if err != nil {
+		return err
+	}
+
+	auroraConfigName := AO.Affiliation
+	if flagAuroraConfig != "" {
+		auroraConfigName = flagAuroraConfig
+	}
+
+	apiClient, err := getAPIClient(auroraConfigName, pFlagToken, flagCluster)
+	if err != nil {
+		return err
+	}
+
+	applications, err := service.GetApplications(apiClient, strings.Join(args, "/"), "", flagExcludes, cmd.OutOrStdout())
+	if err != nil {
+		return err
+	} else if len(applications) == 0 {
+		return errors.New("No applications found")
+	}
+
+	specs, err := service.GetFilteredDeploymentSpecs(apiClient, applications, flagCluster)
+	if err != nil {
+		return err
+	}
+
+	specs = selector.Filter(specs)
+	if len(specs) == 0 {
+		return errors.New("No applications found")
+	}
+
+	partitions, err := createDeploySpecPartitions(auroraConfigName, pFlagToken, AO.Clusters, specs)
+	if err != nil {
+		return err
+	}
+
+	statuses := getApplicationStatuses(getApplicationDeploymentClient, getDeploymentStatusClient, partitions)
+	printApplicationStatuses(statuses, cmd.OutOrStdout())
+
+	return nil
+}
+
+func getDeploymentStatusClient(partition Partition) client.DeploymentStatusClient {
+	token := partition.Cluster.Token
+	if partition.OverrideToken != "" {
+		token = partition.OverrideToken
+	}
+	return client.NewOpenShiftClient(partition.Cluster.Url, token)
+}
+
+// getApplicationStatuses queries every partition concurrently. Failures are reported per application
+// so that one unreachable cluster does not hide the state of the others.
+func getApplicationStatuses(getClient func(partition Partition) client.ApplicationDeploymentClient, getStatusClient func(partition Partition) client.DeploymentStatusClient, partitions []DeploySpecPartition) []applicationStatus {
+	partialStatuses := make(chan []applicationStatus)
+
+	for _, partition := range partitions {
+		go func(partition DeploySpecPartition) {
+			partialStatuses <- getPartitionStatuses(getClient(partition.Partition), getStatusClient(partition.Partition), partition)
+		}(partition)
+	}
+
+	var allStatuses []applicationStatus
+	for i := 0; i < len(partitions); i++ {
+		allStatuses = append(allStatuses, <-partialStatuses...)
+	}
+
+	sort.Slice(allStatuses, func(i, j int) bool {
+		a, b := allStatuses[i], allStatuses[j]
+		if a.Cluster != b.Cluster {
+			return a.Cluster < b.Cluster
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Spec.Name() < b.Spec.Name()
+	})
+
+	return allStatuses
+}
+
+func getPartitionStatuses(deployClient client.ApplicationDeploymentClient, statusClient client.DeploymentStatusClient, partition DeploySpecPartition) []applicationStatus {
+	statuses := make([]applicationStatus, len(partition.DeploySpecs))
+	var applicationList []string
+	for i, spec := range partition.DeploySpecs {
+		statuses[i] = applicationStatus{
+			Spec:      spec,
+			Cluster:   partition.Cluster.Name,
+			Namespace: spec.GetString("namespace"),
+		}
+		applicationList = append(applicationList, spec.GetString("applicationDeploymentRef"))
+	}
+
+	setError := func(message string) []applicationStatus {
+		for i := range statuses {
+			statuses[i].Error = message
+		}
+		return statuses
+	}
+
+	if !partition.Cluster.Reachable {
+		return setError("Cluster is not reachable")
+	}
+
+	existsResults, err := deployClient.Exists(client.NewExistsPayload(applicationList))
+	if err != nil {
+		return setError(err.Error())
+	} else if !existsResults.Success {
+		return setError("Failed to retrieve application deployment information from cluster")
+	}
+
+	// The results are matched by namespace and name, Boober does not promise to keep the order of the refs
+	results := make(map[string]client.ExistsResult)
+	for _, existsResult := range existsResults.Results {
+		results[existsResult.ApplicationRef.Namespace+"/"+existsResult.ApplicationRef.Name] = existsResult
+	}
+
+	for i := range statuses {
+		existsResult, found := results[statuses[i].Namespace+"/"+statuses[i].Spec.Name()]
+		if !found {
+			statuses[i].Error = "Missing from the response when checking existence"
+			continue
+		} else if !existsResult.Success {
+			statuses[i].Error = failureReason(existsResult.Message)
+			continue
+		} else if !existsResult.Exists {
+			continue
+		}
+
+		deployment, err := statusClient.GetDeploymentStatus(statuses[i].Namespace, statuses[i].Spec.Name())
+		if err != nil {
+			statuses[i].Error = err.Error()
+			continue
+		}
+		statuses[i].Deployment = *deployment
+	}
+
+	return statuses
+}
+
+// paused returns true if the application is paused in the AuroraConfig and scaled down. An application
+// configured with zero replicas is scaled down without being paused.
+func (s applicationStatus) paused() bool {
+	return s.Spec.GetBool("pause") && s.Deployment.ScaledDown()
+}
+
+// runsConfiguredVersion returns false if the deployed version is known and differs from the AuroraConfig
+func (s applicationStatus) runsConfiguredVersion() bool {
+	return !s.Spec.HasValue("version") || s.Deployment.Version == "-" || s.Deployment.Version == s.Spec.Version()
+}
+
+// drift lists the fields where the running application differs from the AuroraConfig
+func (s applicationStatus) drift() []string {
+	if s.Error != "" || !s.Deployment.Exists {
+		return nil
+	}
+
+	var drift []string
+	if !s.runsConfiguredVersion() {
+		drift = append(drift, "version")
+	}
+
+	paused := s.Spec.GetBool("pause")
+	if paused && !s.Deployment.ScaledDown() {
+		drift = append(drift, "pause")
+	}
+
+	if !paused && s.Spec.HasValue("replicas") && s.Spec.GetString("replicas") != fmt.Sprintf("%d", s.Deployment.Replicas) {
+		drift = append(drift, "replicas")
+	}
+
+	return drift
+}
+
+func getApplicationStatusTable(statuses []applicationStatus) (string, []string) {
+	var rows []string
+	for _, s := range statuses {
+		exists, version, paused, replicas := "-", "-", "-", "-"
+		var status string
+
+		switch {
+		case s.Error != "":
+			status = fmt.Sprintf("\x1b[31m%s\x1b[0m", s.Error)
+		case !s.Deployment.Exists:
+			exists = "false"
+			status = "\x1b[31mNot deployed\x1b[0m"
+		default:
+			exists = "true"
+			version = s.Deployment.Version
+			paused = fmt.Sprintf("%t", s.paused())
+			replicas = fmt.Sprintf("%d/%d", s.Deployment.AvailableReplicas, s.Deployment.Replicas)
+			if drift := s.drift(); len(drift) > 0 {
+				status = fmt.Sprintf("\x1b[33mDrift: %s\x1b[0m", strings.Join(drift, ", "))
+			} else {
+				status = "\x1b[32mIn sync\x1b[0m"
+			}
+		}
+
+		configuredReplicas := s.Spec.GetString("replicas")
+		if s.Spec.GetBool("pause") {
+			configuredReplicas = "0"
+		}
+
+		pattern := "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s"
+		rows = append(rows, fmt.Sprintf(pattern,
+			s.Cluster,
+			s.Namespace,
+			s.Spec.Name(),
+			exists,
+			fmt.Sprintf("%s (%s)", version, s.Spec.Version()),
+			fmt.Sprintf("%s (%t)", paused, s.Spec.GetBool("pause")),
+			fmt.Sprintf("%s (%s)", replicas, configuredReplicas),
+			status,
+		))
+	}
+
+	header := "CLUSTER\tNAMESPACE\tAPPLICATION\tEXISTS\tVERSION (CONFIGURED)\tPAUSED (CONFIGURED)\tREPLICAS (CONFIGURED)\t\x1b[00mSTATUS\x1b[0m"
+	return header, rows
+}
+
+func printApplicationStatuses(statuses []applicationStatus, out io.Writer) {
+	header, rows := getApplicationStatusTable(statuses)
+	DefaultTablePrinter(header, rows, out)
+}
@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/skatteetaten/ao/pkg/client"
+	"github.com/skatteetaten/ao/pkg/deploymentspec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newStatusTestSpec(name, version, replicas string, pause bool) deploymentspec.DeploymentSpec {
+	spec := deploymentspec.NewDeploymentSpec(name, "dev", "east", version)
+	spec["namespace"] = map[string]interface{}{"value": "sales-dev"}
+	spec["replicas"] = map[string]interface{}{"value": replicas}
+	spec["pause"] = map[string]interface{}{"value": pause}
+	return spec
+}
+
+func Test_getApplicationStatuses(t *testing.T) {
+	specs := []deploymentspec.DeploymentSpec{
+		newStatusTestSpec("crm", "1.2.3", "2", false),
+		newStatusTestSpec("erp", "2", "1", true),
+		newStatusTestSpec("sap", "3", "1", false),
+		newStatusTestSpec("hr", "4", "1", false),
+	}
+
+	deployClientMock := client.NewApplicationDeploymentClientMock()
+	deployClientMock.On("Exists", mock.Anything).Times(1)
+	// The results are not in the order of the requested refs
+	deployClientMock.ExistsResults = []client.ExistsResult{
+		{Success: true, Exists: false, ApplicationRef: *client.NewApplicationRef("sales-dev", "sap")},
+		{Success: false, Message: "Forbidden", ApplicationRef: *client.NewApplicationRef("sales-dev", "hr")},
+		{Success: true, Exists: true, ApplicationRef: *client.NewApplicationRef("sales-dev", "erp")},
+		{Success: true, Exists: true, ApplicationRef: *client.NewApplicationRef("sales-dev", "crm")},
+	}
+	statusClientMock := client.NewDeploymentStatusClientMock(
+		client.DeploymentStatus{Namespace: "sales-dev", Name: "crm", Exists: true, Version: "1.2.3", Replicas: 2, AvailableReplicas: 2},
+		client.DeploymentStatus{Namespace: "sales-dev", Name: "erp", Exists: true, Version: "1", Replicas: 1, AvailableReplicas: 1},
+	)
+	statusClientMock.On("GetDeploymentStatus", mock.Anything, mock.Anything)
+
+	partitions := []DeploySpecPartition{
+		*newDeploySpecPartition(specs, *newTestCluster("east", true), "sales", ""),
+		*newDeploySpecPartition(testSpecs[11:12], *newTestCluster("north", false), "sales", ""),
+	}
+
+	statuses := getApplicationStatuses(
+		func(partition Partition) client.ApplicationDeploymentClient { return deployClientMock },
+		func(partition Partition) client.DeploymentStatusClient { return statusClientMock },
+		partitions,
+	)
+
+	assert.Len(t, statuses, 5)
+
+	assert.Equal(t, "crm", statuses[0].Spec.Name())
+	assert.Equal(t, "sales-dev", statuses[0].Namespace)
+	assert.Empty(t, statuses[0].drift())
+
+	assert.Equal(t, "erp", statuses[1].Spec.Name())
+	assert.Equal(t, []string{"version", "pause"}, statuses[1].drift())
+
+	assert.Equal(t, "hr", statuses[2].Spec.Name())
+	assert.Equal(t, "Forbidden", statuses[2].Error)
+
+	assert.Equal(t, "sap", statuses[3].Spec.Name())
+	assert.Empty(t, statuses[3].Error)
+	assert.False(t, statuses[3].Deployment.Exists)
+
+	assert.Equal(t, "north", statuses[4].Cluster)
+	assert.Equal(t, "Cluster is not reachable", statuses[4].Error)
+
+	deployClientMock.AssertExpectations(t)
+	statusClientMock.AssertNumberOfCalls(t, "GetDeploymentStatus", 2)
+
+	header, rows := getApplicationStatusTable(statuses)
+	assert.Equal(t, "CLUSTER\tNAMESPACE\tAPPLICATION\tEXISTS\tVERSION (CONFIGURED)\tPAUSED (CONFIGURED)\tREPLICAS (CONFIGURED)\t\x1b[00mSTATUS\x1b[0m", header)
+	assert.Equal(t, "east\tsales-dev\tcrm\ttrue\t1.2.3 (1.2.3)\tfalse (false)\t2/2 (2)\t\x1b[32mIn sync\x1b[0m", rows[0])
+	assert.Equal(t, "east\tsales-dev\terp\ttrue\t1 (2)\tfalse (true)\t1/1 (0)\t\x1b[33mDrift: version, pause\x1b[0m", rows[1])
+	assert.Equal(t, "east\tsales-dev\tsap\tfalse\t- (3)\t- (false)\t- (1)\t\x1b[31mNot deployed\x1b[0m", rows[3])
+}
+
+func Test_applicationStatus_drift(t *testing.T) {
+	scaledDown := client.DeploymentStatus{Namespace: "sales-dev", Name: "crm", Exists: true, Version: "1.2.3"}
+
+	status := applicationStatus{Spec: newStatusTestSpec("crm", "1.2.3", "0", false), Deployment: scaledDown}
+	assert.Empty(t, status.drift())
+	assert.False(t, status.paused())
+
+	status = applicationStatus{Spec: newStatusTestSpec("crm", "1.2.3", "2", true), Deployment: scaledDown}
+	assert.Empty(t, status.drift())
+	assert.True(t, status.paused())
+
+	status = applicationStatus{Spec: newStatusTestSpec("crm", "1.2.3", "2", false), Deployment: scaledDown}
+	assert.Equal(t, []string{"replicas"}, status.drift())
+}
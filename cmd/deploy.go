@@ -1,22 +1,38 @@
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
 	"io"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/skatteetaten/ao/pkg/client"
 	"github.com/skatteetaten/ao/pkg/deploymentspec"
+	"github.com/skatteetaten/ao/pkg/policy"
 	"github.com/skatteetaten/ao/pkg/prompt"
+	"github.com/skatteetaten/ao/pkg/report"
 	"github.com/skatteetaten/ao/pkg/service"
 	"github.com/spf13/cobra"
 )
 
 const deployLong = `Deploys applications from the current AuroraConfig.
 For use in CI environments use --no-prompt to disable interactivity.
+
+Deploys are checked against the deploy policies in deployPolicies in the config
+and in ao-policies.json in the AuroraConfig, e.g.
+  {"policies": [{"name": "production", "environments": ["prod.*"], "protected": true,
+    "disallowOverrides": true, "deniedVersions": "SNAPSHOT", "deployHours": "08:00-15:00",
+    "deployDays": ["mon", "tue", "wed", "thu"]}]}
+Protected environments must be confirmed by typing the environment name.
+ao-policies.json is always read from master, a deploy with --ref can only add policies.
+
+The outcome of the deploy is posted to the webhooks in notifications in the config
+and in ao-notifications.json in the AuroraConfig, e.g.
+  {"webhooks": [{"name": "team", "url": "https://hooks.slack.com/...", "format": "slack"}]}
+Supported formats are json (default), cloudevents, slack and teams. A template may replace
+the message text, or the whole body for json.
 `
 
 const exampleDeploy = `  Given the following AuroraConfig:
@@ -37,17 +53,38 @@ const exampleDeploy = `  Given the following AuroraConfig:
 
   # Deploy an application with override for application file
   ao deploy foo/bar -o 'foo/bar.json:{"pause": true}'
+
+  # Deploy an application with overrides read from a file, e.g. {"foo/bar.json": {"pause": true}}
+  ao deploy foo/bar -o @overrides.json
+
+  # Deploy an application with a single overridden value
+  ao deploy foo/bar --set 'foo/bar:/config/LOG_LEVEL=DEBUG'
 	
   # Exclude application(s) from foo environment (regexp)
   ao deploy foo -e .*/bar -e .*/baz
 
   # Exclude environment(s) when deploying an application across environments (regexp)
   ao deploy bar -e ref/.*
+
+  # Redeploy every paused application on the utv cluster
+  ao deploy --selector 'pause,cluster=utv'
+
+  # Deploy all applications in foo with a snapshot version of type deploy
+  ao deploy foo --selector 'type=deploy,version~=SNAPSHOT'
+
+  # Deploy and write a JUnit report for the CI server
+  ao deploy foo --no-prompt --report junit=build/ao-deploy.xml
+
+  # Deploy despite a policy violation, the reason is recorded in 'ao history'
+  ao deploy prod/bar --override-policy 'Hotfix for incident 1234'
+
+  # Deploy to prod-relay first and continue to prod only if every deploy succeeded
+  ao deploy prod/bar --strategy staged --stage-order prod-relay,prod --stage-confirm
 `
 
 var deployCmd = &cobra.Command{
 	Aliases:     []string{"setup", "apply"},
-	Use:         "deploy <applicationDeploymentRef>",
+	Use:         "deploy [applicationDeploymentRef]",
 	Short:       "Deploy one or more ApplicationDeploymentRef (environment/application) to one or more clusters",
 	Long:        deployLong,
 	Example:     exampleDeploy,
@@ -61,9 +98,18 @@ func init() {
 	deployCmd.Flags().StringVarP(&flagAuroraConfig, "auroraconfig", "a", "", "Overrides the logged in AuroraConfig")
 	deployCmd.Flags().StringVarP(&flagCluster, "cluster", "c", "", "Limit deploy to given cluster name")
 	deployCmd.Flags().BoolVarP(&flagNoPrompt, "no-prompt", "", false, "Suppress prompts")
-	deployCmd.Flags().StringArrayVarP(&flagOverrides, "overrides", "o", []string{}, "Override in the form '[env/]file:{<json override>}'")
+	deployCmd.Flags().StringArrayVarP(&flagOverrides, "overrides", "o", []string{}, "Override in the form "+overrideFormat)
+	deployCmd.Flags().StringArrayVarP(&flagSets, "set", "", []string{}, "Override a single value in the form '[env/]file:/json/path=value'")
 	deployCmd.Flags().StringArrayVarP(&flagExcludes, "exclude", "e", []string{}, "Select applications or environments to exclude from deploy")
 	deployCmd.Flags().StringVarP(&flagVersion, "version", "v", "", "Set the given version in AuroraConfig before deploy")
+	deployCmd.Flags().StringVarP(&flagSelector, "selector", "s", "", "Select applications by deployment spec fields, e.g. 'type=deploy,cluster in (utv,test),version~=SNAPSHOT,!pause'")
+	deployCmd.Flags().StringArrayVarP(&flagReports, "report", "", []string{}, reportUsage)
+	deployCmd.Flags().StringVarP(&flagStrategy, "strategy", "", strategyParallel, "Rollout strategy, 'parallel' deploys to all clusters at once, 'staged' deploys to one cluster at a time")
+	deployCmd.Flags().StringSliceVarP(&flagStageOrder, "stage-order", "", []string{}, "Cluster order for staged rollout, defaults to deployStageOrder in config")
+	deployCmd.Flags().BoolVarP(&flagStageConfirm, "stage-confirm", "", false, "Ask for confirmation before continuing to the next stage")
+	deployCmd.Flags().StringVarP(&flagOverridePolicy, "override-policy", "", "", "Deploy despite policy violations, the given reason is recorded in the journal")
+	deployCmd.Flags().DurationVarP(&flagStageDelay, "stage-delay", "", 0, "Wait the given duration before continuing to the next stage, e.g. 5m")
+	deployCmd.Flags().DurationVarP(&flagStageTimeout, "stage-timeout", "", defaultRolloutTimeout, "Abort the rollout if a stage is not rolled out within the given duration")
 
 	deployCmd.Flags().BoolVarP(&flagNoPrompt, "force", "f", false, "Suppress prompts")
 	deployCmd.Flags().MarkHidden("force")
@@ -73,7 +119,7 @@ func init() {
 
 func deploy(cmd *cobra.Command, args []string) error {
 
-	if len(args) > 2 || len(args) < 1 {
+	if len(args) > 2 || (len(args) < 1 && flagSelector == "") {
 		return cmd.Usage()
 	}
 
@@ -82,9 +128,25 @@ func deploy(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	search := args[0]
-	if len(args) == 2 {
-		search = fmt.Sprintf("%s/%s", args[0], args[1])
+	if err := validateStrategyParams(cmd.Flags().Changed("stage-timeout")); err != nil {
+		return err
+	}
+
+	reportTargets, err := report.ParseTargets(flagReports)
+	if err != nil {
+		return err
+	}
+
+	selector, err := deploymentspec.ParseSelector(flagSelector)
+	if err != nil {
+		return err
+	}
+
+	search := strings.Join(args, "/")
+
+	overrideArgs, err := parseOverride(flagOverrides, flagSets)
+	if err != nil {
+		return err
 	}
 
 	auroraConfigName := AO.Affiliation
@@ -97,40 +159,123 @@ func deploy(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	applications, err := service.GetApplications(apiClient, search, flagVersion, flagExcludes, cmd.OutOrStdout())
+	applications, err := service.GetApplications(apiClient, search, "", flagExcludes, cmd.OutOrStdout())
 	if err != nil {
 		return err
 	} else if len(applications) == 0 {
 		return errors.New("No applications to deploy")
+	} else if flagVersion != "" && len(applications) > 1 {
+		return errors.New("Deploy with version does only support one application")
 	}
 
-	filteredDeploymentSpecs, err := service.GetFilteredDeploymentSpecs(apiClient, applications, flagCluster)
+	filteredDeploymentSpecs, err := getSelectedDeploymentSpecs(apiClient, applications, selector)
 	if err != nil {
 		return err
 	}
 
-	overrideConfig, err := parseOverride(flagOverrides)
+	fileNames, err := apiClient.GetFileNames()
 	if err != nil {
 		return err
 	}
 
-	partitions, err := createDeploySpecPartitions(auroraConfigName, pFlagToken, AO.Clusters, filteredDeploymentSpecs)
+	overrides, err := overrideArgs.resolveFileNames(fileNames)
+	if err != nil {
+		return err
+	}
+
+	overrideConfig, err := overrides.toPayload()
 	if err != nil {
 		return err
 	}
 
-	if !getDeployConfirmation(flagNoPrompt, filteredDeploymentSpecs, cmd.OutOrStdout()) {
+	policies, err := loadDeployPolicies(apiClient, policyRefClient(apiClient), fileNames)
+	if err != nil {
+		return err
+	}
+
+	policyReport, err := policies.Evaluate(policy.Deploy{
+		Specs:        filteredDeploymentSpecs,
+		Version:      flagVersion,
+		HasOverrides: len(overrides) > 0,
+		Interactive:  !flagNoPrompt,
+		Time:         time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+
+	err = enforceDeployPolicies(policyReport, flagOverridePolicy, auroraConfigName, apiClient.RefName, cmd.OutOrStdout(), cmd.ErrOrStderr())
+	if err != nil {
+		return err
+	}
+
+	if flagVersion != "" {
+		fmt.Fprintf(cmd.OutOrStdout(), "%s will be updated with /version %s before deploy\n\n", applications[0], flagVersion)
+	}
+
+	if !getDeployConfirmation(flagNoPrompt, filteredDeploymentSpecs, overrides, cmd.OutOrStdout()) {
 		return errors.New("No applications to deploy")
 	}
 
-	result, err := deployToReachableClusters(getApplicationDeploymentClient, partitions, overrideConfig)
+	if err := confirmProtectedEnvironments(policyReport.Protected, prompt.Input); err != nil {
+		return err
+	}
+
+	// The version is set after policies and confirmations, so that a rejected deploy never changes the AuroraConfig
+	if flagVersion != "" {
+		err = service.UpdateVersion(apiClient, applications[0], flagVersion, cmd.OutOrStdout())
+		if err != nil {
+			return err
+		}
+
+		filteredDeploymentSpecs, err = getSelectedDeploymentSpecs(apiClient, applications, selector)
+		if err != nil {
+			return err
+		}
+	}
+
+	partitions, err := createDeploySpecPartitions(auroraConfigName, pFlagToken, AO.Clusters, filteredDeploymentSpecs)
+	if err != nil {
+		return err
+	}
+
+	var result []client.DeployResults
+	if flagStrategy == strategyStaged {
+		order := flagStageOrder
+		if len(order) == 0 {
+			order = AO.DeployStageOrder
+		}
+		stages := createDeployStages(partitions, order)
+		healthCheck := newStageHealthCheck(getApplicationDeploymentClient, getDeploymentStatusClient, flagStageTimeout, cmd.OutOrStdout())
+		gate := newStageGate(flagStageConfirm, flagStageDelay, cmd.OutOrStdout())
+		result, err = deployInStages(getApplicationDeploymentClient, stages, overrideConfig, healthCheck, gate, cmd.OutOrStdout())
+	} else {
+		result, err = deployToReachableClusters(getApplicationDeploymentClient, partitions, overrideConfig)
+	}
 	if err != nil {
 		return err
 	}
 
+	recordDeployResults(auroraConfigName, apiClient.RefName, result, cmd.ErrOrStderr())
+	notifyDeployResults(apiClient, auroraConfigName, apiClient.RefName, result, cmd.ErrOrStderr())
+
 	printDeployResult(result, cmd.OutOrStdout())
 
-	return nil
+	return report.Write(reportTargets, newDeployReport(auroraConfigName, result))
+}
+
+func getSelectedDeploymentSpecs(apiClient client.DeploySpecClient, applications []string, selector deploymentspec.Selector) ([]deploymentspec.DeploymentSpec, error) {
+	filteredDeploymentSpecs, err := service.GetFilteredDeploymentSpecs(apiClient, applications, flagCluster)
+	if err != nil {
+		return nil, err
+	}
+
+	filteredDeploymentSpecs = selector.Filter(filteredDeploymentSpecs)
+	if len(filteredDeploymentSpecs) == 0 {
+		return nil, errors.New("No applications to deploy")
+	}
+
+	return filteredDeploymentSpecs, nil
 }
 
 func validateParams() error {
@@ -144,27 +289,16 @@ func validateParams() error {
 	return nil
 }
 
-func parseOverride(overrides []string) (map[string]string, error) {
-	returnMap := make(map[string]string)
-	for i := 0; i < len(overrides); i++ {
-		indexByte := strings.IndexByte(overrides[i], ':')
-		filename := overrides[i][:indexByte]
-		jsonOverride := overrides[i][indexByte+1:]
-
-		if !json.Valid([]byte(jsonOverride)) {
-			msg := fmt.Sprintf("%s is not a valid json", jsonOverride)
-			return nil, errors.New(msg)
-		}
-
-		returnMap[filename] = jsonOverride
-	}
-	return returnMap, nil
-}
-
-func getDeployConfirmation(force bool, filteredDeploymentSpecs []deploymentspec.DeploymentSpec, out io.Writer) bool {
+func getDeployConfirmation(force bool, filteredDeploymentSpecs []deploymentspec.DeploymentSpec, overrides overrideMap, out io.Writer) bool {
 	header, rows := GetDeploySpecTable(filteredDeploymentSpecs)
 	DefaultTablePrinter(header, rows, out)
 
+	if len(overrides) > 0 {
+		fmt.Fprintln(out, "")
+		overrideHeader, overrideRows := getOverrideTable(overrides)
+		DefaultTablePrinter(overrideHeader, overrideRows, out)
+	}
+
 	shouldDeploy := true
 	if !force {
 		defaultAnswer := len(rows) == 1
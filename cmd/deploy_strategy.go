@@ -0,0 +1,212 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/skatteetaten/ao/pkg/client"
+	"github.com/skatteetaten/ao/pkg/prompt"
+)
+
+const (
+	strategyParallel = "parallel"
+	strategyStaged   = "staged"
+)
+
+var (
+	flagStrategy     string
+	flagStageOrder   []string
+	flagStageConfirm bool
+	flagStageDelay   time.Duration
+	flagStageTimeout time.Duration
+)
+
+// defaultRolloutTimeout is how long a rollout is waited for, unless another timeout is given
+const defaultRolloutTimeout = 10 * time.Minute
+
+// stageHealthInterval is how often the applications of a stage are polled while waiting for them to roll out
+var stageHealthInterval = 5 * time.Second
+
+type deployStage struct {
+	Cluster    string
+	Partitions []DeploySpecPartition
+}
+
+// stageGate is called after a stage has succeeded and decides whether the rollout continues to the next stage
+type stageGate func(completed, next deployStage) bool
+
+// stageHealthCheck waits until the applications deployed in a stage are rolled out, and returns an error
+// if any of them failed or did not roll out in time
+type stageHealthCheck func(stage deployStage) error
+
+// validateStrategyParams checks the rollout flags. The stage timeout has a default, so whether it was
+// given is passed as stageTimeoutChanged.
+func validateStrategyParams(stageTimeoutChanged bool) error {
+	switch flagStrategy {
+	case strategyParallel:
+		if len(flagStageOrder) > 0 || flagStageConfirm || flagStageDelay > 0 || stageTimeoutChanged {
+			return errors.New("Stage options can only be used with --strategy staged")
+		}
+	case strategyStaged:
+		if flagStageConfirm && flagNoPrompt {
+			return errors.New("--stage-confirm can not be combined with --no-prompt")
+		} else if flagStageTimeout <= 0 {
+			return errors.New("--stage-timeout must be a positive duration")
+		}
+	default:
+		return errors.Errorf("Unknown strategy %s, must be one of [%s, %s]", flagStrategy, strategyParallel, strategyStaged)
+	}
+
+	return nil
+}
+
+// createDeployStages groups partitions by cluster. Clusters in order are deployed first,
+// in the given order, followed by the remaining clusters sorted by name.
+func createDeployStages(partitions []DeploySpecPartition, order []string) []deployStage {
+	stageMap := make(map[string]*deployStage)
+	for _, partition := range partitions {
+		name := partition.Cluster.Name
+		if _, exists := stageMap[name]; !exists {
+			stageMap[name] = &deployStage{Cluster: name}
+		}
+		stageMap[name].Partitions = append(stageMap[name].Partitions, partition)
+	}
+
+	var stages []deployStage
+	for _, name := range order {
+		if stage, exists := stageMap[name]; exists {
+			stages = append(stages, *stage)
+			delete(stageMap, name)
+		}
+	}
+
+	var remaining []string
+	for name := range stageMap {
+		remaining = append(remaining, name)
+	}
+	sort.Strings(remaining)
+	for _, name := range remaining {
+		stages = append(stages, *stageMap[name])
+	}
+
+	return stages
+}
+
+// deployInStages deploys one cluster at a time. The next stage starts when the applications of the previous
+// stage are rolled out. The rollout is aborted if a stage fails, is not healthy or the gate refuses to
+// continue, and the remaining partitions are reported as failed.
+func deployInStages(getClient func(partition Partition) client.ApplicationDeploymentClient, stages []deployStage, overrideConfig map[string]string, healthCheck stageHealthCheck, gate stageGate, out io.Writer) ([]client.DeployResults, error) {
+	var allResults []client.DeployResults
+
+	for i, stage := range stages {
+		fmt.Fprintf(out, "Stage %d/%d: deploying to %s\n", i+1, len(stages), stage.Cluster)
+
+		results, err := deployToReachableClusters(getClient, stage.Partitions, overrideConfig)
+		if err != nil {
+			return nil, err
+		}
+		allResults = append(allResults, results...)
+
+		if i == len(stages)-1 {
+			break
+		}
+
+		reason := ""
+		if !deploysSucceeded(results) {
+			reason = fmt.Sprintf("Rollout aborted, stage %s failed", stage.Cluster)
+		} else if err := healthCheck(stage); err != nil {
+			reason = fmt.Sprintf("Rollout aborted, stage %s is not healthy: %s", stage.Cluster, err)
+		} else if gate != nil && !gate(stage, stages[i+1]) {
+			reason = fmt.Sprintf("Rollout aborted after stage %s", stage.Cluster)
+		}
+
+		if reason != "" {
+			fmt.Fprintln(out, reason)
+			for _, remaining := range stages[i+1:] {
+				for _, partition := range remaining.Partitions {
+					allResults = append(allResults, errorDeployResults(reason, partition))
+				}
+			}
+			break
+		}
+	}
+
+	return allResults, nil
+}
+
+func newStageHealthCheck(getClient func(partition Partition) client.ApplicationDeploymentClient, getStatusClient func(partition Partition) client.DeploymentStatusClient, timeout time.Duration, out io.Writer) stageHealthCheck {
+	return func(stage deployStage) error {
+		fmt.Fprintf(out, "Waiting for the applications in %s to roll out\n", stage.Cluster)
+		return waitForRollout(getClient, getStatusClient, stage.Partitions, timeout)
+	}
+}
+
+// waitForRollout polls the applications in partitions until they are rolled out, and returns an error if
+// any of them failed or did not roll out within timeout
+func waitForRollout(getClient func(partition Partition) client.ApplicationDeploymentClient, getStatusClient func(partition Partition) client.DeploymentStatusClient, partitions []DeploySpecPartition, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		pending, failed := getRolloutState(getApplicationStatuses(getClient, getStatusClient, partitions))
+		if len(failed) > 0 {
+			return errors.New(strings.Join(failed, ", "))
+		} else if len(pending) == 0 {
+			return nil
+		} else if time.Now().After(deadline) {
+			return errors.Errorf("%s did not roll out within %s", strings.Join(pending, ", "), timeout)
+		}
+		time.Sleep(stageHealthInterval)
+	}
+}
+
+// getRolloutState lists the applications that are not rolled out yet, and the applications that failed with
+// the reason. An application is pending until it runs the deployed version, so that the previous rollout is
+// not mistaken for the new one before OpenShift has seen the change.
+func getRolloutState(statuses []applicationStatus) (pending []string, failed []string) {
+	for _, status := range statuses {
+		name := status.Namespace + "/" + status.Spec.Name()
+		if status.Error != "" {
+			failed = append(failed, fmt.Sprintf("%s: %s", name, status.Error))
+		} else if status.Deployment.RolloutFailed {
+			failed = append(failed, fmt.Sprintf("%s: rollout failed", name))
+		} else if !status.Deployment.Exists || !status.Deployment.RolledOut || !status.runsConfiguredVersion() {
+			pending = append(pending, name)
+		}
+	}
+	return pending, failed
+}
+
+func newStageGate(confirm bool, delay time.Duration, out io.Writer) stageGate {
+	return func(completed, next deployStage) bool {
+		if confirm {
+			message := fmt.Sprintf("Stage %s succeeded. Continue rollout to %s?", completed.Cluster, next.Cluster)
+			if !prompt.Confirm(message, false) {
+				return false
+			}
+		}
+
+		if delay > 0 {
+			fmt.Fprintf(out, "Waiting %s before deploying to %s\n", delay, next.Cluster)
+			time.Sleep(delay)
+		}
+
+		return true
+	}
+}
+
+func deploysSucceeded(results []client.DeployResults) bool {
+	for _, result := range results {
+		if !result.Success {
+			return false
+		}
+		for _, deploy := range result.Results {
+			if !deploy.Ignored && !deploy.Success {
+				return false
+			}
+		}
+	}
+	return true
+}
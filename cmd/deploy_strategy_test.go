@@ -0,0 +1,189 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/skatteetaten/ao/pkg/client"
+	"github.com/skatteetaten/ao/pkg/deploymentspec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_createDeployStages(t *testing.T) {
+	partitions := []DeploySpecPartition{
+		*newDeploySpecPartition(testSpecs[0:3], *newTestCluster("east", true), "jupiter", ""),
+		*newDeploySpecPartition(testSpecs[3:7], *newTestCluster("west", true), "jupiter", ""),
+		*newDeploySpecPartition(testSpecs[7:11], *newTestCluster("west", true), "jupiter", ""),
+		*newDeploySpecPartition(testSpecs[11:13], *newTestCluster("north", true), "jupiter", ""),
+	}
+
+	stages := createDeployStages(partitions, []string{"west", "south"})
+
+	assert.Len(t, stages, 3)
+	assert.Equal(t, "west", stages[0].Cluster)
+	assert.Len(t, stages[0].Partitions, 2)
+	assert.Equal(t, "east", stages[1].Cluster)
+	assert.Equal(t, "north", stages[2].Cluster)
+}
+
+func Test_validateStrategyParams(t *testing.T) {
+	defer func() {
+		flagStrategy, flagStageOrder, flagStageTimeout = strategyParallel, nil, 0
+	}()
+
+	flagStrategy, flagStageTimeout = strategyParallel, 10*time.Minute
+	assert.NoError(t, validateStrategyParams(false))
+	assert.EqualError(t, validateStrategyParams(true), "Stage options can only be used with --strategy staged")
+
+	flagStageOrder = []string{"east"}
+	assert.EqualError(t, validateStrategyParams(false), "Stage options can only be used with --strategy staged")
+
+	flagStrategy = strategyStaged
+	assert.NoError(t, validateStrategyParams(true))
+
+	flagStageTimeout = 0
+	assert.EqualError(t, validateStrategyParams(true), "--stage-timeout must be a positive duration")
+}
+
+func Test_deployInStages(t *testing.T) {
+	getPartitions := func(firstReachable bool) []deployStage {
+		return createDeployStages([]DeploySpecPartition{
+			*newDeploySpecPartition(testSpecs[0:3], *newTestCluster("east", firstReachable), "jupiter", ""),
+			*newDeploySpecPartition(testSpecs[3:7], *newTestCluster("west", true), "jupiter", ""),
+		}, []string{"east", "west"})
+	}
+	healthy := func(stage deployStage) error {
+		return nil
+	}
+
+	t.Run("Should deploy all stages when each stage succeeds", func(t *testing.T) {
+		deployClientMock := client.NewApplicationDeploymentClientMock()
+		deployClientMock.On("Deploy", mock.Anything).Times(2)
+		getClient := func(partition Partition) client.ApplicationDeploymentClient {
+			return deployClientMock
+		}
+
+		var gated []string
+		gate := func(completed, next deployStage) bool {
+			gated = append(gated, completed.Cluster+"->"+next.Cluster)
+			return true
+		}
+
+		results, err := deployInStages(getClient, getPartitions(true), map[string]string{}, healthy, gate, new(bytes.Buffer))
+
+		assert.NoError(t, err)
+		assert.Len(t, results, 2)
+		assert.Equal(t, []string{"east->west"}, gated)
+		deployClientMock.AssertExpectations(t)
+	})
+
+	t.Run("Should abort remaining stages when a stage fails", func(t *testing.T) {
+		deployClientMock := client.NewApplicationDeploymentClientMock()
+		getClient := func(partition Partition) client.ApplicationDeploymentClient {
+			return deployClientMock
+		}
+
+		results, err := deployInStages(getClient, getPartitions(false), map[string]string{}, healthy, nil, new(bytes.Buffer))
+
+		assert.NoError(t, err)
+		assert.Len(t, results, 2)
+		assert.Equal(t, "Cluster is not reachable", results[0].Message)
+		assert.False(t, results[1].Success)
+		assert.Len(t, results[1].Results, 4)
+		assert.Equal(t, "Rollout aborted, stage east failed", results[1].Results[0].Reason)
+		deployClientMock.AssertNotCalled(t, "Deploy")
+	})
+
+	t.Run("Should abort remaining stages when the gate refuses to continue", func(t *testing.T) {
+		deployClientMock := client.NewApplicationDeploymentClientMock()
+		deployClientMock.On("Deploy", mock.Anything).Times(1)
+		getClient := func(partition Partition) client.ApplicationDeploymentClient {
+			return deployClientMock
+		}
+
+		gate := func(completed, next deployStage) bool {
+			return false
+		}
+
+		results, err := deployInStages(getClient, getPartitions(true), map[string]string{}, healthy, gate, new(bytes.Buffer))
+
+		assert.NoError(t, err)
+		assert.Len(t, results, 2)
+		assert.True(t, results[0].Success)
+		assert.Equal(t, "Rollout aborted after stage east", results[1].Message)
+		deployClientMock.AssertExpectations(t)
+	})
+
+	t.Run("Should abort remaining stages when a stage does not roll out", func(t *testing.T) {
+		deployClientMock := client.NewApplicationDeploymentClientMock()
+		deployClientMock.On("Deploy", mock.Anything).Times(1)
+		getClient := func(partition Partition) client.ApplicationDeploymentClient {
+			return deployClientMock
+		}
+
+		unhealthy := func(stage deployStage) error {
+			return errors.New("rollout of sales-dev/crm failed")
+		}
+		gate := func(completed, next deployStage) bool {
+			t.Error("gate should not be called when a stage is not healthy")
+			return true
+		}
+
+		results, err := deployInStages(getClient, getPartitions(true), map[string]string{}, unhealthy, gate, new(bytes.Buffer))
+
+		assert.NoError(t, err)
+		assert.Len(t, results, 2)
+		assert.True(t, results[0].Success)
+		assert.Equal(t, "Rollout aborted, stage east is not healthy: rollout of sales-dev/crm failed", results[1].Message)
+		deployClientMock.AssertExpectations(t)
+	})
+}
+
+func Test_getRolloutState(t *testing.T) {
+	statuses := []applicationStatus{
+		{Spec: newStatusTestSpec("crm", "1", "2", false), Namespace: "sales-dev", Deployment: client.DeploymentStatus{Exists: true, Version: "1", Replicas: 2, AvailableReplicas: 2, RolledOut: true}},
+		{Spec: newStatusTestSpec("cms", "2", "1", false), Namespace: "sales-dev", Deployment: client.DeploymentStatus{Exists: true, Version: "1", Replicas: 1, AvailableReplicas: 1, RolledOut: true}},
+		{Spec: newStatusTestSpec("erp", "1", "2", false), Namespace: "sales-dev", Deployment: client.DeploymentStatus{Exists: true, Replicas: 2, AvailableReplicas: 1}},
+		{Spec: newStatusTestSpec("sap", "1", "1", false), Namespace: "sales-dev", Deployment: client.DeploymentStatus{Exists: true, Replicas: 1, RolloutFailed: true}},
+		{Spec: newStatusTestSpec("hr", "1", "1", false), Namespace: "sales-dev"},
+		{Spec: newStatusTestSpec("web", "1", "1", false), Namespace: "sales-dev", Error: "Cluster is not reachable"},
+	}
+
+	pending, failed := getRolloutState(statuses)
+
+	assert.Equal(t, []string{"sales-dev/cms", "sales-dev/erp", "sales-dev/hr"}, pending)
+	assert.Equal(t, []string{"sales-dev/sap: rollout failed", "sales-dev/web: Cluster is not reachable"}, failed)
+}
+
+func Test_newStageHealthCheck(t *testing.T) {
+	stage := deployStage{
+		Cluster: "east",
+		Partitions: []DeploySpecPartition{
+			*newDeploySpecPartition([]deploymentspec.DeploymentSpec{newStatusTestSpec("crm", "1", "2", false)}, *newTestCluster("east", true), "sales", ""),
+		},
+	}
+	getClient := func(partition Partition) client.ApplicationDeploymentClient {
+		deployClientMock := client.NewApplicationDeploymentClientMock()
+		deployClientMock.On("Exists", mock.Anything)
+		deployClientMock.ExistsResults = []client.ExistsResult{{Success: true, Exists: true, ApplicationRef: *client.NewApplicationRef("sales-dev", "crm")}}
+		return deployClientMock
+	}
+	getStatusClient := func(status client.DeploymentStatus) func(partition Partition) client.DeploymentStatusClient {
+		return func(partition Partition) client.DeploymentStatusClient {
+			statusClientMock := client.NewDeploymentStatusClientMock(status)
+			statusClientMock.On("GetDeploymentStatus", mock.Anything, mock.Anything)
+			return statusClientMock
+		}
+	}
+
+	rolledOut := client.DeploymentStatus{Namespace: "sales-dev", Name: "crm", Exists: true, Version: "1", Replicas: 2, AvailableReplicas: 2, RolledOut: true}
+	healthCheck := newStageHealthCheck(getClient, getStatusClient(rolledOut), 0, new(bytes.Buffer))
+	assert.NoError(t, healthCheck(stage))
+
+	rollingOut := client.DeploymentStatus{Namespace: "sales-dev", Name: "crm", Exists: true, Version: "1", Replicas: 2, AvailableReplicas: 1}
+	healthCheck = newStageHealthCheck(getClient, getStatusClient(rollingOut), 0, new(bytes.Buffer))
+	assert.EqualError(t, healthCheck(stage), "sales-dev/crm did not roll out within 0s")
+}
@@ -2,14 +2,22 @@ package cmd
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/pkg/errors"
 	"github.com/skatteetaten/ao/pkg/auroraconfig"
+	"github.com/skatteetaten/ao/pkg/client"
 	"github.com/skatteetaten/ao/pkg/editor"
 	"github.com/spf13/cobra"
 )
 
-const editLong = `Edit a single file in the current AuroraConfig.`
+const editLong = `Edit one or more files in the current AuroraConfig.
+Several files are edited in one buffer and saved separately, only the files that fail are reopened.
+JSON and YAML syntax is checked before the file is saved, and with --schema also unknown fields and
+fields with the wrong type.
+If someone else saves the file while it is edited, their changes are merged with yours. Fields changed
+by both are marked as conflicts and the file is reopened to resolve them.`
 
 const exampleEdit = `  Given the following AuroraConfig:
     - about.json
@@ -24,22 +32,42 @@ const exampleEdit = `  Given the following AuroraConfig:
 
   # Fuzzy matching: will open foo/foobar.json in editor
   ao edit fofoba
+
+  # Edit foo/about.json and foo/bar.json together
+  ao edit foo/about foo/bar
+
+  # Edit all files in the foo environment
+  ao edit foo/
+
+  # Edit bar.json in all environments
+  ao edit '*/bar'
 `
 
 var editCmd = &cobra.Command{
-	Use:         "edit [env/]file",
-	Short:       "Edit a single file in the AuroraConfig repository",
+	Use:         "edit [env/]file...",
+	Short:       "Edit one or more files in the AuroraConfig repository",
 	Long:        editLong,
 	Annotations: map[string]string{"type": "remote"},
 	Example:     exampleEdit,
 	RunE:        EditFile,
 }
 
+var (
+	flagEditSchema  bool
+	flagEditRecover bool
+)
+
 func init() {
 	RootCmd.AddCommand(editCmd)
+	editCmd.Flags().BoolVarP(&flagEditSchema, "schema", "", false, "Check the file against the AuroraConfig schema before saving")
+	editCmd.Flags().BoolVarP(&flagEditRecover, "recover", "", false, "List unsaved edits, or resume the edit with the given id. Unsaved edits are kept for 7 days, secrets for 24 hours")
 }
 
 func EditFile(cmd *cobra.Command, args []string) error {
+	if flagEditRecover {
+		return recoverEdit(cmd, args)
+	}
+
 	if len(args) < 1 {
 		return cmd.Usage()
 	}
@@ -49,34 +77,165 @@ func EditFile(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	search := args[0]
-	if len(args) == 2 {
-		search = fmt.Sprintf("%s/%s", args[0], args[1])
+	files, err := findFilesToEdit(fileNames, args)
+	if err != nil {
+		return err
 	}
 
-	matches := auroraconfig.FindMatches(search, fileNames, true)
-	if len(matches) == 0 {
-		return errors.Errorf("No matches for %s", search)
-	} else if len(matches) > 1 {
-		return errors.Errorf("Search matched more than one file. Search must be more specific.\n%v", matches)
+	var sessions []*editSession
+	var documents []editor.Document
+	for _, fileName := range files {
+		session, file, err := newEditSession(DefaultApiClient, fileName)
+		if err != nil {
+			return err
+		}
+		sessions = append(sessions, session)
+		documents = append(documents, editor.Document{Name: file.Name, Content: file.Contents, OnSave: session.save})
 	}
 
-	fileName := matches[0]
-	file, eTag, err := DefaultApiClient.GetAuroraConfigFile(fileName)
+	return editFiles(sessions, documents)
+}
+
+// editFiles opens a single file with the editor, and several files as one buffer
+func editFiles(sessions []*editSession, documents []editor.Document) error {
+	fileEditor := editor.NewEditor(nil)
+	fileEditor.CheckSchema = flagEditSchema
+	fileEditor.RecoveryDir = recoveryDir()
+	fileEditor.Recovery = editor.RecoveredEdit{
+		Kind:        editor.RecoveredFileEdit,
+		Affiliation: DefaultApiClient.Affiliation,
+	}
+	for _, session := range sessions {
+		fileEditor.Recovery.Files = append(fileEditor.Recovery.Files, editor.RecoveredFile{Name: session.fileName, Base: session.base, ETag: session.eTag})
+	}
+
+	if len(documents) == 1 {
+		fileEditor.OnSave = documents[0].OnSave
+		fileEditor.Unsaved = documents[0].Unsaved
+
+		err := fileEditor.Edit(documents[0].Content, documents[0].Name)
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(documents[0].Name, "edited")
+		return nil
+	}
+
+	saved, err := fileEditor.EditDocuments(documents)
+	for _, fileName := range saved {
+		fmt.Println(fileName, "edited")
+	}
+
+	return err
+}
+
+// findFilesToEdit finds one file for each search. A search ending with / selects all files in
+// an environment, and a glob pattern selects all matching files. For backwards compatibility
+// "ao edit env app" is the same as "ao edit env/app".
+func findFilesToEdit(fileNames auroraconfig.FileNames, searches []string) ([]string, error) {
+	if len(searches) == 2 && !strings.ContainsAny(searches[0]+searches[1], "/*?[") {
+		for _, environment := range fileNames.GetEnvironments() {
+			if environment == searches[0] {
+				searches = []string{fmt.Sprintf("%s/%s", searches[0], searches[1])}
+				break
+			}
+		}
+	}
+
+	var files []string
+	seen := make(map[string]bool)
+	for _, search := range searches {
+		var matches []string
+		if strings.HasSuffix(search, "/") {
+			for _, fileName := range fileNames {
+				if strings.HasPrefix(fileName, search) {
+					matches = append(matches, fileName)
+				}
+			}
+			sort.Strings(matches)
+		} else if strings.ContainsAny(search, "*?[") {
+			selected, err := auroraconfig.SelectFiles(search, fileNames)
+			if err != nil {
+				return nil, err
+			}
+			matches = selected
+		} else {
+			matches = auroraconfig.FindMatches(search, fileNames, true)
+			if len(matches) > 1 {
+				return nil, errors.Errorf("Search %s matched more than one file. Search must be more specific.\n%v", search, matches)
+			}
+		}
+
+		if len(matches) == 0 {
+			return nil, errors.Errorf("No matches for %s", search)
+		}
+
+		for _, match := range matches {
+			if !seen[match] {
+				seen[match] = true
+				files = append(files, match)
+			}
+		}
+	}
+
+	return files, nil
+}
+
+func newEditSession(apiClient client.AuroraConfigClient, fileName string) (*editSession, *auroraconfig.AuroraConfigFile, error) {
+	file, eTag, err := apiClient.GetAuroraConfigFile(fileName)
 	if err != nil {
-		return err
+		return nil, nil, err
+	}
+
+	session := &editSession{
+		apiClient: apiClient,
+		fileName:  fileName,
+		base:      file.Contents,
+		eTag:      eTag,
+	}
+
+	return session, file, nil
+}
+
+// editSession saves an edited file. If the file has been changed by someone else since it was fetched,
+// the changes are merged and saved if there are no conflicts.
+type editSession struct {
+	apiClient client.AuroraConfigClient
+	fileName  string
+	base      string
+	eTag      string
+}
+
+func (s *editSession) save(modified string) error {
+	file := &auroraconfig.AuroraConfigFile{
+		Name:     s.fileName,
+		Contents: modified,
 	}
 
-	fileEditor := editor.NewEditor(func(modified string) error {
-		file.Contents = modified
-		return DefaultApiClient.PutAuroraConfigFile(file, eTag)
-	})
+	err := s.apiClient.PutAuroraConfigFile(file, s.eTag)
+	if errors.Cause(err) != client.ErrFileChanged {
+		return err
+	}
 
-	err = fileEditor.Edit(string(file.Contents), file.Name)
+	theirs, eTag, err := s.apiClient.GetAuroraConfigFile(s.fileName)
 	if err != nil {
 		return err
 	}
 
-	fmt.Println(fileName, "edited")
-	return nil
+	merged, conflicts, err := auroraconfig.MergeFile(s.fileName, s.base, modified, theirs.Contents)
+	if err != nil {
+		return errors.Wrapf(err, "%s and could not be merged", client.ErrFileChanged)
+	}
+
+	s.base, s.eTag = theirs.Contents, eTag
+
+	if len(conflicts) > 0 {
+		return &editor.ConflictError{
+			Message: fmt.Sprintf("%s has been changed by someone else, resolve the conflicts in %s", s.fileName, strings.Join(conflicts, ", ")),
+			Content: merged,
+		}
+	}
+
+	return s.save(merged)
 }
@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/skatteetaten/ao/pkg/editor"
+	"github.com/spf13/cobra"
+)
+
+func recoveryDir() string {
+	return filepath.Join(ConfigDir, "recovery")
+}
+
+// recoverEdit lists the unsaved edits without arguments, or resumes the edit given by id
+func recoverEdit(cmd *cobra.Command, args []string) error {
+	if err := editor.RemoveExpiredEdits(recoveryDir(), time.Now()); err != nil {
+		fmt.Fprintln(cmd.ErrOrStderr(), "Warning:", err)
+	}
+
+	if len(args) == 0 {
+		edits, err := editor.ListRecoveredEdits(recoveryDir())
+		if err != nil {
+			return err
+		}
+		if len(edits) == 0 {
+			cmd.Println("No unsaved edits")
+			return nil
+		}
+		printRecoveredEdits(edits, cmd.OutOrStdout())
+		return nil
+	} else if len(args) > 1 {
+		return cmd.Usage()
+	}
+
+	id := args[0]
+	edit, contents, err := editor.LoadRecoveredEdit(recoveryDir(), id)
+	if err != nil {
+		return err
+	}
+
+	if edit.Affiliation != DefaultApiClient.Affiliation {
+		return errors.Errorf("Edit %s belongs to affiliation %s, login to %s to recover it", id, edit.Affiliation, edit.Affiliation)
+	}
+
+	cmd.Printf("Resuming edit of %s from %s\n", strings.Join(edit.Names(), ", "), edit.Started.Format("2006-01-02 15:04"))
+
+	if edit.Kind == editor.RecoveredSecretEdit {
+		err = resumeSecretEdit(cmd, edit, contents)
+	} else {
+		err = resumeFileEdit(edit, contents)
+	}
+
+	// The resumed edit is either saved or kept again with a new id
+	if removeErr := editor.RemoveRecoveredEdit(recoveryDir(), id); removeErr != nil {
+		return removeErr
+	}
+
+	return err
+}
+
+func resumeFileEdit(edit *editor.RecoveredEdit, contents map[string]string) error {
+	var sessions []*editSession
+	var documents []editor.Document
+	for _, file := range edit.Files {
+		content, exists := contents[file.Name]
+		if !exists {
+			continue
+		}
+
+		session := &editSession{
+			apiClient: DefaultApiClient,
+			fileName:  file.Name,
+			base:      file.Base,
+			eTag:      file.ETag,
+		}
+		sessions = append(sessions, session)
+		documents = append(documents, editor.Document{Name: file.Name, Content: content, OnSave: session.save, Unsaved: true})
+	}
+
+	if len(documents) == 0 {
+		return errors.New("The edit has no unsaved files")
+	}
+
+	return editFiles(sessions, documents)
+}
+
+func printRecoveredEdits(edits []editor.RecoveredEdit, out io.Writer) {
+	var rows []string
+	for _, edit := range edits {
+		rows = append(rows, fmt.Sprintf("%s\t%s\t%s\t%s\t%s", edit.ID, edit.Kind, edit.Affiliation, edit.Started.Format("2006-01-02 15:04"), strings.Join(edit.Names(), ", ")))
+	}
+	DefaultTablePrinter("ID\tKIND\tAFFILIATION\tSTARTED\tFILES", rows, out)
+}
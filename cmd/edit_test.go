@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/skatteetaten/ao/pkg/auroraconfig"
+	"github.com/skatteetaten/ao/pkg/client"
+	"github.com/skatteetaten/ao/pkg/editor"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_editSession_save(t *testing.T) {
+	base := "{\n  \"version\": \"1\",\n  \"replicas\": 1\n}\n"
+
+	t.Run("Should merge and save when file has changed", func(t *testing.T) {
+		apiClient := client.NewAuroraConfigClientMock(auroraconfig.FileNames{"dev/crm.json"})
+		apiClient.Files = map[string]string{"dev/crm.json": "{\n  \"version\": \"1\",\n  \"replicas\": 2\n}\n"}
+
+		mine := "{\n  \"version\": \"2\",\n  \"replicas\": 1\n}\n"
+		merged := "{\n  \"version\": \"2\",\n  \"replicas\": 2\n}\n"
+		apiClient.On("PutAuroraConfigFile", "dev/crm.json", mine, "etag").Return(client.ErrFileChanged).Once()
+		apiClient.On("PutAuroraConfigFile", "dev/crm.json", merged, "dev/crm.json").Return(nil).Once()
+
+		session := &editSession{apiClient: apiClient, fileName: "dev/crm.json", base: base, eTag: "etag"}
+		err := session.save(mine)
+
+		assert.NoError(t, err)
+		apiClient.AssertExpectations(t)
+	})
+
+	t.Run("Should return conflict markers when both changed the same field", func(t *testing.T) {
+		apiClient := client.NewAuroraConfigClientMock(auroraconfig.FileNames{"dev/crm.json"})
+		apiClient.Files = map[string]string{"dev/crm.json": "{\n  \"version\": \"1\",\n  \"replicas\": 2\n}\n"}
+
+		mine := "{\n  \"version\": \"1\",\n  \"replicas\": 3\n}\n"
+		apiClient.On("PutAuroraConfigFile", "dev/crm.json", mine, "etag").Return(client.ErrFileChanged).Once()
+
+		session := &editSession{apiClient: apiClient, fileName: "dev/crm.json", base: base, eTag: "etag"}
+		err := session.save(mine)
+
+		conflict, ok := err.(*editor.ConflictError)
+		assert.True(t, ok)
+		assert.Equal(t, "dev/crm.json has been changed by someone else, resolve the conflicts in /replicas", conflict.Message)
+		assert.True(t, auroraconfig.HasConflictMarkers(conflict.Content))
+		assert.Equal(t, apiClient.Files["dev/crm.json"], session.base)
+		assert.Equal(t, "dev/crm.json", session.eTag)
+	})
+}
+
+func Test_findFilesToEdit(t *testing.T) {
+	fileNames := auroraconfig.FileNames{"about.json", "bar.json", "foo/about.json", "foo/bar.json", "foo/foobar.json", "test/bar.yaml"}
+
+	tests := []struct {
+		Searches []string
+		Expected []string
+	}{
+		{[]string{"foo/bar"}, []string{"foo/bar.json"}},
+		{[]string{"foo", "bar"}, []string{"foo/bar.json"}},
+		{[]string{"fofoba"}, []string{"foo/foobar.json"}},
+		{[]string{"foo/about", "foo/bar", "foo/about.json"}, []string{"foo/about.json", "foo/bar.json"}},
+		{[]string{"foo/"}, []string{"foo/about.json", "foo/bar.json", "foo/foobar.json"}},
+		{[]string{"*/bar"}, []string{"foo/bar.json", "test/bar.yaml"}},
+	}
+
+	for _, test := range tests {
+		files, err := findFilesToEdit(fileNames, test.Searches)
+		assert.NoError(t, err)
+		assert.Equal(t, test.Expected, files, test.Searches)
+	}
+
+	_, err := findFilesToEdit(fileNames, []string{"bar", "prod/"})
+	assert.EqualError(t, err, "No matches for prod/")
+
+	_, err = findFilesToEdit(fileNames, []string{"fo"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/skatteetaten/ao/pkg/deploymentspec"
+	"github.com/spf13/cobra"
+)
+
+const explainLong = `Show the value of a field in the deployment spec of an application, the file that set it and the
+files that also set the field but were overridden, the most specific first.
+If the field is an object, e.g. config, every field in it is shown.
+
+The deployment spec is fetched from Boober, or with --local merged from the local AuroraConfig like
+'ao render' does.`
+
+const exampleExplain = `  # Show where the value of config/LOG_LEVEL comes from
+  ao explain utv/crm /config/LOG_LEVEL
+
+  # Show all resource fields from the local AuroraConfig
+  ao explain utv/crm resources --local`
+
+var flagExplainLocal bool
+
+var explainCmd = &cobra.Command{
+	Use:         "explain <env/app> <field>",
+	Short:       "Show where the value of a field in the deployment spec comes from",
+	Long:        explainLong,
+	Example:     exampleExplain,
+	Annotations: map[string]string{"type": "remote"},
+	RunE:        Explain,
+}
+
+func init() {
+	RootCmd.AddCommand(explainCmd)
+	explainCmd.Flags().BoolVarP(&flagExplainLocal, "local", "", false, "Merge the local AuroraConfig instead of fetching the deployment spec from Boober")
+}
+
+func Explain(cmd *cobra.Command, args []string) error {
+	if len(args) != 2 {
+		return cmd.Usage()
+	}
+
+	var spec deploymentspec.DeploymentSpec
+	if flagExplainLocal {
+		ac, err := collectLocalAuroraConfig()
+		if err != nil {
+			return err
+		}
+
+		spec, err = renderLocalSpec(ac.Files, args[0])
+		if err != nil {
+			return err
+		}
+	} else {
+		fileNames, err := DefaultApiClient.GetFileNames()
+		if err != nil {
+			return err
+		}
+
+		ref, err := findApplicationDeploymentRef(args[0], fileNames)
+		if err != nil {
+			return err
+		}
+
+		specs, err := DefaultApiClient.GetAuroraDeploySpec([]string{ref}, true)
+		if err != nil {
+			return err
+		}
+		if len(specs) == 0 {
+			return errors.Errorf("No deployment spec for %s", ref)
+		}
+		spec = specs[0]
+	}
+
+	header, rows, err := getExplainTable(spec, args[1])
+	if err != nil {
+		return err
+	}
+
+	DefaultTablePrinter(header, rows, cmd.OutOrStdout())
+	return nil
+}
+
+// getExplainTable lists the sources of the field, or of every field in it if it is an object
+func getExplainTable(spec deploymentspec.DeploymentSpec, field string) (string, []string, error) {
+	pointer := "/" + strings.Trim(field, "/")
+
+	var fields []string
+	for _, candidate := range spec.Fields() {
+		if candidate == pointer || strings.HasPrefix(candidate, pointer+"/") {
+			fields = append(fields, candidate)
+		}
+	}
+	if len(fields) == 0 {
+		return "", nil, errors.Errorf("%s has no field %s", spec.GetString("applicationDeploymentRef"), pointer)
+	}
+
+	var rows []string
+	for _, name := range fields {
+		rows = append(rows, fmt.Sprintf("%s\t%s\t%s\t%s", name, formatSpecValue(spec.Get(name)), spec.Source(name), "set"))
+
+		sources := spec.Sources(name)
+		for i := len(sources) - 2; i >= 0; i-- {
+			rows = append(rows, fmt.Sprintf("\t%s\t%s\t%s", formatSpecValue(sources[i].Value), sources[i].Name, "overridden"))
+		}
+	}
+
+	header := "FIELD\tVALUE\tSOURCE\tSTATUS"
+	return header, rows, nil
+}
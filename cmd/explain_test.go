@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_getExplainTable(t *testing.T) {
+	spec, err := renderLocalSpec(localFiles, "utv-relay/crm")
+	assert.NoError(t, err)
+
+	header, rows, err := getExplainTable(spec, "/version")
+	assert.NoError(t, err)
+	assert.Equal(t, "FIELD\tVALUE\tSOURCE\tSTATUS", header)
+	assert.Equal(t, []string{
+		"/version\t1.1.0\tutv-relay/crm.json\tset",
+		"\t1.0.0\tcrm.json\toverridden",
+	}, rows)
+
+	_, rows, err = getExplainTable(spec, "cluster")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"/cluster\tutv-relay\tutv-relay/about.json\tset",
+		"\tutv\tabout.json\toverridden",
+	}, rows)
+
+	_, rows, err = getExplainTable(spec, "config/")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"/config/LOG_LEVEL\tINFO\tcrm.json\tset"}, rows)
+
+	_, _, err = getExplainTable(spec, "/config/FOO")
+	assert.EqualError(t, err, "utv-relay/crm has no field /config/FOO")
+}
@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/skatteetaten/ao/pkg/auroraconfig"
+	"github.com/skatteetaten/ao/pkg/versioncontrol"
+	"github.com/spf13/cobra"
+)
+
+const formatLong = `Format the JSON and YAML files in the local AuroraConfig.
+
+JSON and YAML files are indented with two spaces and end with a newline. Fields in the
+AuroraConfig schema are ordered as in the schema, e.g. type and version first, followed by other
+fields in alphabetical order. Fields in free form objects like config are ordered alphabetically.
+Arrays are never reordered, and comments in YAML files are kept.
+
+Without paths all files in the current git repository are formatted. Hidden folders and the files
+read by ao itself, like ao-policies.json, are skipped. The names of the formatted files are printed.
+
+With --check no files are changed, the unformatted files are printed and the command fails if there
+are any, e.g. in a CI build.`
+
+const exampleFormat = `  # Format all files in the AuroraConfig
+  ao fmt
+
+  # Format the files in the utv environment and about.json
+  ao fmt utv about.json
+
+  # Fail if any file is not formatted
+  ao fmt --check`
+
+var flagFormatCheck bool
+
+var formatCmd = &cobra.Command{
+	Use:         "fmt [path...]",
+	Short:       "Format the JSON and YAML files in the local AuroraConfig",
+	Long:        formatLong,
+	Example:     exampleFormat,
+	Annotations: map[string]string{"type": "local"},
+	RunE:        Format,
+}
+
+func init() {
+	RootCmd.AddCommand(formatCmd)
+	formatCmd.Flags().BoolVarP(&flagFormatCheck, "check", "", false, "Only list files that are not formatted, and fail if there are any")
+}
+
+func Format(cmd *cobra.Command, args []string) error {
+	paths := args
+	if len(paths) == 0 {
+		wd, err := os.Getwd()
+		if err != nil {
+			return err
+		}
+
+		gitRoot, err := versioncontrol.FindGitPath(wd)
+		if err != nil {
+			return err
+		}
+		paths = []string{gitRoot}
+	}
+
+	files, err := findFilesToFormat(paths)
+	if err != nil {
+		return err
+	}
+
+	unformatted, err := formatFiles(files, flagFormatCheck, cmd.OutOrStdout())
+	if err != nil {
+		return err
+	}
+
+	if flagFormatCheck && len(unformatted) > 0 {
+		return errors.Errorf("%d file(s) are not formatted, run ao fmt to format them", len(unformatted))
+	}
+	return nil
+}
+
+// findFilesToFormat returns the JSON and YAML files given in paths, folders are searched recursively
+func findFilesToFormat(paths []string) ([]string, error) {
+	var files []string
+	for _, root := range paths {
+		info, err := os.Stat(root)
+		if err != nil {
+			return nil, err
+		}
+
+		if !info.IsDir() {
+			if !isFormattable(root) {
+				return nil, errors.Errorf("%s is not a JSON or YAML file", root)
+			}
+			files = append(files, root)
+			continue
+		}
+
+		err = filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+
+			if info.IsDir() {
+				if path != root && strings.HasPrefix(info.Name(), ".") {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+
+			if ignored, _ := filepath.Match("ao-*.json", info.Name()); !ignored && isFormattable(path) {
+				files = append(files, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return files, nil
+}
+
+func isFormattable(fileName string) bool {
+	return strings.ToLower(filepath.Ext(fileName)) == ".json" || auroraconfig.IsYAML(fileName)
+}
+
+// formatFiles formats the files and prints the names of the files that were not already formatted. With check
+// the files are not changed. Every file is attempted, and an error is returned if any file is invalid.
+func formatFiles(files []string, check bool, out io.Writer) ([]string, error) {
+	var unformatted []string
+	failed := 0
+	for _, fileName := range files {
+		changed, err := formatFile(fileName, check)
+		if err != nil {
+			fmt.Fprintf(out, "%s: %s\n", fileName, err)
+			failed++
+			continue
+		}
+
+		if changed {
+			fmt.Fprintln(out, fileName)
+			unformatted = append(unformatted, fileName)
+		}
+	}
+
+	if failed > 0 {
+		return unformatted, errors.Errorf("%d file(s) could not be formatted", failed)
+	}
+	return unformatted, nil
+}
+
+// formatFile returns true if the file was not formatted. Unless check is set the file is rewritten.
+func formatFile(fileName string, check bool) (bool, error) {
+	info, err := os.Stat(fileName)
+	if err != nil {
+		return false, err
+	}
+
+	contents, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return false, err
+	}
+
+	formatted, err := auroraconfig.FormatFile(fileName, string(contents))
+	if err != nil {
+		return false, err
+	}
+
+	if formatted == string(contents) {
+		return false, nil
+	}
+
+	if check {
+		return true, nil
+	}
+
+	return true, ioutil.WriteFile(fileName, []byte(formatted), info.Mode())
+}
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_formatFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ao-fmt")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"about.json":       "{\n  \"affiliation\": \"paas\"\n}\n",
+		"crm.json":         `{"version": "1", "type": "deploy"}`,
+		"utv/crm.yaml":     "version: 1 # pinned\n",
+		"utv/invalid.json": `{"version": }`,
+		"ao-policies.json": `{"policies": []}`,
+		".git/config.json": `{}`,
+		"README.md":        "# AuroraConfig",
+	}
+	for name, contents := range files {
+		path := filepath.Join(dir, name)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		assert.NoError(t, ioutil.WriteFile(path, []byte(contents), 0644))
+	}
+
+	found, err := findFilesToFormat([]string{dir})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(dir, "about.json"),
+		filepath.Join(dir, "crm.json"),
+		filepath.Join(dir, "utv/crm.yaml"),
+		filepath.Join(dir, "utv/invalid.json"),
+	}, found)
+
+	_, err = findFilesToFormat([]string{filepath.Join(dir, "README.md")})
+	assert.Error(t, err)
+
+	out := &bytes.Buffer{}
+	unformatted, err := formatFiles(found, true, out)
+	assert.EqualError(t, err, "1 file(s) could not be formatted")
+	assert.Equal(t, []string{filepath.Join(dir, "crm.json")}, unformatted)
+	assert.Contains(t, out.String(), filepath.Join(dir, "utv/invalid.json")+": Invalid JSON format at line 1")
+
+	data, _ := ioutil.ReadFile(filepath.Join(dir, "crm.json"))
+	assert.Equal(t, files["crm.json"], string(data))
+
+	unformatted, err = formatFiles(found[:3], false, &bytes.Buffer{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(dir, "crm.json")}, unformatted)
+
+	data, _ = ioutil.ReadFile(filepath.Join(dir, "crm.json"))
+	assert.Equal(t, "{\n  \"type\": \"deploy\",\n  \"version\": \"1\"\n}\n", string(data))
+
+	unformatted, err = formatFiles(found[:3], true, &bytes.Buffer{})
+	assert.NoError(t, err)
+	assert.Empty(t, unformatted)
+}
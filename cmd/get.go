@@ -72,6 +72,8 @@ func init() {
 	getSpecCmd.Flags().BoolVarP(&flagNoDefaults, "no-defaults", "", false, "exclude default values from output")
 	getSpecCmd.Flags().BoolVarP(&flagJSON, "json", "", false, "print deploy spec as json")
 	getDeploymentsCmd.Flags().BoolVarP(&flagAsList, "list", "", false, "print ApplicationDeploymentRefs as a list")
+	getAppsCmd.Flags().StringVarP(&flagSelector, "selector", "s", "", "Select applications by deployment spec fields, e.g. 'type=deploy,cluster in (utv,test),!pause'")
+	getEnvsCmd.Flags().StringVarP(&flagSelector, "selector", "s", "", "Select applications by deployment spec fields, e.g. 'type=deploy,cluster in (utv,test),!pause'")
 }
 
 func PrintAll(cmd *cobra.Command, args []string) error {
@@ -106,7 +108,7 @@ func PrintApplications(cmd *cobra.Command, args []string) error {
 	if len(fileNames.GetApplications()) < 1 {
 		return errors.New("No applications available")
 	}
-	if len(args) > 0 {
+	if len(args) > 0 || flagSelector != "" {
 		return PrintDeploySpecTable(args, auroraconfig.APP_FILTER, cmd, fileNames)
 	}
 
@@ -125,7 +127,7 @@ func PrintEnvironments(cmd *cobra.Command, args []string) error {
 		return errors.New("No environments available")
 	}
 
-	if len(args) > 0 {
+	if len(args) > 0 || flagSelector != "" {
 		return PrintDeploySpecTable(args, auroraconfig.ENV_FILTER, cmd, fileNames)
 	}
 
@@ -135,6 +137,11 @@ func PrintEnvironments(cmd *cobra.Command, args []string) error {
 }
 
 func PrintDeploySpecTable(args []string, filter auroraconfig.FilterMode, cmd *cobra.Command, fileNames auroraconfig.FileNames) error {
+	selector, err := deploymentspec.ParseSelector(flagSelector)
+	if err != nil {
+		return err
+	}
+
 	var selected []string
 	for _, arg := range args {
 		matches := auroraconfig.FindAllDeploysFor(filter, arg, fileNames.GetApplicationDeploymentRefs())
@@ -143,10 +150,20 @@ func PrintDeploySpecTable(args []string, filter auroraconfig.FilterMode, cmd *co
 		}
 		selected = append(selected, matches...)
 	}
+	if len(args) == 0 {
+		selected = fileNames.GetApplicationDeploymentRefs()
+	}
+
 	specs, err := DefaultApiClient.GetAuroraDeploySpec(selected, true)
 	if err != nil {
 		return err
 	}
+
+	specs = selector.Filter(specs)
+	if len(specs) == 0 {
+		return errors.Errorf("No applications match selector %s", flagSelector)
+	}
+
 	header, rows := GetDeploySpecTable(specs)
 	DefaultTablePrinter(header, rows, cmd.OutOrStdout())
 	return nil
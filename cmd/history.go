@@ -0,0 +1,168 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/skatteetaten/ao/pkg/client"
+	"github.com/skatteetaten/ao/pkg/journal"
+	"github.com/spf13/cobra"
+)
+
+const historyLong = `Show deploys and deletes performed from this machine.
+Every deploy and delete outcome is recorded in a local journal, including the DeployId
+needed by 'ao inspect'.`
+
+const exampleHistory = `  # Show all recorded deploys and deletes for the current AuroraConfig
+  ao history
+
+  # Show history for foo/bar during the last week
+  ao history foo/bar --since 7d
+
+  # Show history for all applications in the foo environment since a given date
+  ao history foo --since 2019-11-01`
+
+var flagSince string
+
+var historyCmd = &cobra.Command{
+	Use:         "history [applicationDeploymentRef]",
+	Short:       "Show deploys and deletes recorded in the local journal",
+	Long:        historyLong,
+	Example:     exampleHistory,
+	Annotations: map[string]string{"type": "actions"},
+	RunE:        History,
+}
+
+func init() {
+	RootCmd.AddCommand(historyCmd)
+	historyCmd.Flags().StringVarP(&flagSince, "since", "", "", "Only show entries newer than a duration (24h, 7d), a date (2006-01-02) or an RFC3339 timestamp")
+	historyCmd.Flags().StringVarP(&flagAuroraConfig, "auroraconfig", "a", "", "Overrides the logged in AuroraConfig")
+}
+
+func History(cmd *cobra.Command, args []string) error {
+	if len(args) > 2 {
+		return cmd.Usage()
+	}
+
+	since, err := journal.ParseSince(flagSince, time.Now())
+	if err != nil {
+		return err
+	}
+
+	filter := journal.Filter{
+		Affiliation: AO.Affiliation,
+		Search:      strings.Join(args, "/"),
+		Since:       since,
+	}
+	if flagAuroraConfig != "" {
+		filter.Affiliation = flagAuroraConfig
+	}
+
+	entries, err := deployJournal().Query(filter)
+	if err != nil {
+		return err
+	}
+
+	if len(entries) == 0 {
+		return errors.New("No history available")
+	}
+
+	header, rows := getHistoryTable(entries)
+	DefaultTablePrinter(header, rows, cmd.OutOrStdout())
+
+	return nil
+}
+
+func getHistoryTable(entries []journal.Entry) (string, []string) {
+	var rows []string
+	pattern := "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s"
+	for _, entry := range entries {
+		row := fmt.Sprintf(pattern,
+			entry.Time.Local().Format("2006-01-02 15:04:05"),
+			entry.User,
+			entry.Status,
+			entry.Cluster,
+			entry.Environment,
+			entry.Application,
+			entry.Version,
+			entry.RefName,
+			entry.DeployId,
+			entry.Reason,
+		)
+		rows = append(rows, row)
+	}
+
+	header := "TIME\tUSER\tSTATUS\tCLUSTER\tENVIRONMENT\tAPPLICATION\tVERSION\tREF\tDEPLOY_ID\tMESSAGE"
+	return header, rows
+}
+
+func deployJournal() *journal.Journal {
+	return journal.NewJournal(filepath.Join(ConfigDir, "journal.jsonl"))
+}
+
+// recordDeployResults appends deploy outcomes to the journal. Failing to write the journal
+// must never fail the deploy itself, so errors are only printed as a warning to errOut.
+func recordDeployResults(affiliation, refName string, results []client.DeployResults, errOut io.Writer) {
+	recordResults(journal.ActionDeploy, affiliation, refName, newDeployApplicationResults(results), errOut)
+}
+
+// recordDeleteResults appends delete outcomes to the journal.
+func recordDeleteResults(affiliation, refName string, results []partialDeleteResult, errOut io.Writer) {
+	recordResults(journal.ActionDelete, affiliation, refName, newDeleteApplicationResults(affiliation, results), errOut)
+}
+
+func recordResults(action, affiliation, refName string, results []applicationResult, errOut io.Writer) {
+	entries := newJournalEntries(time.Now(), currentUserName(), action, affiliation, refName, results)
+	if err := deployJournal().Append(entries...); err != nil {
+		fmt.Fprintln(errOut, "Warning:", err)
+	}
+}
+
+func newJournalEntries(now time.Time, userName, action, affiliation, refName string, results []applicationResult) []journal.Entry {
+	succeeded := journal.StatusDeployed
+	if action == journal.ActionDelete {
+		succeeded = journal.StatusDeleted
+	}
+
+	var entries []journal.Entry
+	for _, result := range results {
+		status := succeeded
+		if !result.Success {
+			status = journal.StatusFailed
+		}
+
+		entries = append(entries, journal.Entry{
+			Time:        now,
+			User:        userName,
+			Action:      action,
+			Affiliation: affiliation,
+			RefName:     refName,
+			Cluster:     result.Cluster,
+			Environment: result.Environment,
+			Application: result.Application,
+			Version:     result.Version,
+			DeployId:    result.DeployId,
+			Status:      status,
+			Reason:      result.Reason,
+		})
+	}
+
+	return entries
+}
+
+func currentUserName() string {
+	if current, err := user.Current(); err == nil {
+		// Windows user names are prefixed with the domain
+		parts := strings.Split(current.Username, "\\")
+		return parts[len(parts)-1]
+	}
+
+	userName, _ := os.LookupEnv("USER")
+	return userName
+}
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/skatteetaten/ao/pkg/client"
+	"github.com/skatteetaten/ao/pkg/deploymentspec"
+	"github.com/skatteetaten/ao/pkg/journal"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_newJournalEntries(t *testing.T) {
+	t.Run("Should record deploys, and skip ignored applications", func(t *testing.T) {
+		now := time.Now()
+		results := []client.DeployResults{
+			{
+				Success: true,
+				Results: []client.DeployResult{
+					{DeployId: "abc", Success: true, DeploymentSpec: deploymentspec.NewDeploymentSpec("crm", "dev", "east", "2")},
+					{DeployId: "-", Ignored: true, DeploymentSpec: deploymentspec.NewDeploymentSpec("erp", "dev", "east", "1")},
+				},
+			},
+			errorDeployResults("Cluster is not reachable", *newDeploySpecPartition(testSpecs[11:12], *newTestCluster("north", false), "sales", "")),
+		}
+
+		entries := newJournalEntries(now, "tester", journal.ActionDeploy, "sales", "master", newDeployApplicationResults(results))
+
+		assert.Len(t, entries, 2)
+		assert.Equal(t, journal.ActionDeploy, entries[0].Action)
+		assert.Equal(t, journal.StatusDeployed, entries[0].Status)
+		assert.Equal(t, "dev/crm", entries[0].ApplicationDeploymentRef())
+		assert.Equal(t, "2", entries[0].Version)
+		assert.Equal(t, "abc", entries[0].DeployId)
+		assert.Equal(t, "tester", entries[0].User)
+		assert.Equal(t, "master", entries[0].RefName)
+
+		assert.Equal(t, journal.StatusFailed, entries[1].Status)
+		assert.Equal(t, "north", entries[1].Cluster)
+		assert.Equal(t, "Cluster is not reachable", entries[1].Reason)
+	})
+
+	t.Run("Should record deletes with the environment of the namespace", func(t *testing.T) {
+		partition := *newDeploymentPartition([]DeploymentInfo{*newDeploymentInfo("sales-dev", "crm", "east")}, *newTestCluster("east", true), "sales", "")
+		results := []partialDeleteResult{
+			newPartialDeleteResults(partition, client.DeleteResults{
+				Success: true,
+				Results: []client.DeleteResult{
+					{Success: true, ApplicationRef: *client.NewApplicationRef("sales-dev", "crm")},
+				},
+			}),
+		}
+
+		entries := newJournalEntries(time.Now(), "tester", journal.ActionDelete, "sales", "master", newDeleteApplicationResults("sales", results))
+
+		assert.Len(t, entries, 1)
+		assert.Equal(t, journal.ActionDelete, entries[0].Action)
+		assert.Equal(t, journal.StatusDeleted, entries[0].Status)
+		assert.Equal(t, "east", entries[0].Cluster)
+		assert.Equal(t, "dev/crm", entries[0].ApplicationDeploymentRef())
+	})
+}
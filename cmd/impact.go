@@ -0,0 +1,204 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/skatteetaten/ao/pkg/auroraconfig"
+	"github.com/skatteetaten/ao/pkg/deploymentspec"
+	"github.com/skatteetaten/ao/pkg/versioncontrol"
+	"github.com/spf13/cobra"
+)
+
+const impactLong = `List the applications in the local AuroraConfig that are merged with a file, e.g. about.json.
+
+If the file has changes that are not committed, the fields that change in the merged configuration
+of every application are listed with the values before and after the change. The changes are found by
+merging the local files like 'ao render' does, and comparing with the file at --against.
+
+In a git pre-push hook the file can be compared with the remote branch, e.g. --against origin/master.`
+
+const exampleImpact = `  # List the applications using utv/about.json, and the values changed by local changes to it
+  ao impact utv/about.json
+
+  # Compare about.json with the remote master branch
+  ao impact about.json --against origin/master`
+
+var flagImpactAgainst string
+
+var impactCmd = &cobra.Command{
+	Use:         "impact <file>",
+	Short:       "List the applications affected by a file and by local changes to it",
+	Long:        impactLong,
+	Example:     exampleImpact,
+	Annotations: map[string]string{"type": "local"},
+	RunE:        Impact,
+}
+
+func init() {
+	RootCmd.AddCommand(impactCmd)
+	impactCmd.Flags().StringVarP(&flagImpactAgainst, "against", "", "HEAD", "The git revision to compare the file with")
+}
+
+func Impact(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return cmd.Usage()
+	}
+
+	gitRoot, err := localGitRoot()
+	if err != nil {
+		return err
+	}
+
+	ac, err := versioncontrol.CollectAuroraConfigFilesInRepo(DefaultApiClient.Affiliation, gitRoot)
+	if err != nil {
+		return err
+	}
+
+	fileName := findImpactFile(gitRoot, ac.Files, args[0])
+	committed, exists, err := versioncontrol.ReadFileAtRevision(gitRoot, flagImpactAgainst, fileName)
+	if err != nil {
+		return err
+	}
+
+	current := findFile(ac.Files, fileName)
+	if current == nil && !exists {
+		return errors.Errorf("could not find %s in AuroraConfig", args[0])
+	}
+
+	unchanged := current != nil && exists && current.Contents == committed
+	refs, changes := getImpact(withFile(ac.Files, fileName, committed, exists), ac.Files, fileName, cmd.ErrOrStderr())
+
+	printImpact(fileName, refs, changes, unchanged, cmd.OutOrStdout())
+	return nil
+}
+
+// findImpactFile returns the name of the file in the AuroraConfig. A path to an existing file from the
+// working directory is preferred over a file name in the AuroraConfig.
+func findImpactFile(gitRoot string, files []auroraconfig.AuroraConfigFile, name string) string {
+	if path, err := filepath.Abs(name); err == nil {
+		if relative, err := filepath.Rel(gitRoot, path); err == nil && !strings.HasPrefix(relative, "..") {
+			// AuroraConfig file names always use forward slashes
+			if fileName := filepath.ToSlash(relative); findFile(files, fileName) != nil {
+				return fileName
+			}
+		}
+	}
+
+	if fileName, err := fileNamesOf(files).Find(name); err == nil {
+		return fileName
+	}
+	return name
+}
+
+// getImpact returns the ApplicationDeploymentRefs merged with fileName before or after the change, and
+// the fields that change in their deployment specs. Applications that can not be rendered are printed as
+// warnings to errOut.
+func getImpact(before, after []auroraconfig.AuroraConfigFile, fileName string, errOut io.Writer) ([]string, []deploymentspec.RefChanges) {
+	beforeRenderer, afterRenderer := deploymentspec.NewRenderer(before), deploymentspec.NewRenderer(after)
+
+	seen := make(map[string]bool)
+	var refs []string
+	for _, files := range [][]auroraconfig.AuroraConfigFile{before, after} {
+		for _, ref := range fileNamesOf(files).GetApplicationDeploymentRefs() {
+			if seen[ref] || !(usesFile(beforeRenderer, ref, fileName) || usesFile(afterRenderer, ref, fileName)) {
+				continue
+			}
+			seen[ref] = true
+			refs = append(refs, ref)
+		}
+	}
+	sort.Strings(refs)
+
+	beforeSpecs := make(map[string]deploymentspec.DeploymentSpec)
+	afterSpecs := make(map[string]deploymentspec.DeploymentSpec)
+	for _, ref := range refs {
+		if spec := renderOrWarn(beforeRenderer, before, ref, errOut); spec != nil {
+			beforeSpecs[ref] = spec
+		}
+		if spec := renderOrWarn(afterRenderer, after, ref, errOut); spec != nil {
+			afterSpecs[ref] = spec
+		}
+	}
+
+	return refs, deploymentspec.DiffSpecs(beforeSpecs, afterSpecs)
+}
+
+func usesFile(renderer *deploymentspec.Renderer, ref, fileName string) bool {
+	layers, err := renderer.Layers(ref)
+	if err != nil {
+		return false
+	}
+	for _, layer := range layers {
+		if layer == fileName {
+			return true
+		}
+	}
+	return false
+}
+
+// renderOrWarn returns nil if the ApplicationDeploymentRef does not exist in files or can not be rendered
+func renderOrWarn(renderer *deploymentspec.Renderer, files []auroraconfig.AuroraConfigFile, ref string, errOut io.Writer) deploymentspec.DeploymentSpec {
+	if _, err := fileNamesOf(files).Find(ref); err != nil {
+		return nil
+	}
+
+	spec, err := renderer.Render(ref)
+	if err != nil {
+		fmt.Fprintf(errOut, "Warning: Could not render %s: %s\n", ref, err)
+		return nil
+	}
+	return spec
+}
+
+func printImpact(fileName string, refs []string, changes []deploymentspec.RefChanges, unchanged bool, out io.Writer) {
+	if unchanged || len(changes) == 0 {
+		if unchanged {
+			fmt.Fprintf(out, "No local changes in %s\n\n", fileName)
+		} else {
+			fmt.Fprintf(out, "The local changes in %s do not change any application\n\n", fileName)
+		}
+		header, rows := GetApplicationDeploymentRefTable(refs)
+		DefaultTablePrinter(header, rows, out)
+		return
+	}
+
+	header, rows := getSpecChangesTable(changes)
+	DefaultTablePrinter(header, rows, out)
+	fmt.Fprintf(out, "\n%d of %d application(s) using %s change\n", len(changes), len(refs), fileName)
+}
+
+// withFile returns a copy of files where fileName has contents, or is removed if it does not exist
+func withFile(files []auroraconfig.AuroraConfigFile, fileName, contents string, exists bool) []auroraconfig.AuroraConfigFile {
+	var result []auroraconfig.AuroraConfigFile
+	for _, file := range files {
+		if file.Name != fileName {
+			result = append(result, file)
+		}
+	}
+	if exists {
+		result = append(result, auroraconfig.AuroraConfigFile{Name: fileName, Contents: contents})
+	}
+	return result
+}
+
+func fileNamesOf(files []auroraconfig.AuroraConfigFile) auroraconfig.FileNames {
+	var fileNames auroraconfig.FileNames
+	for _, file := range files {
+		fileNames = append(fileNames, file.Name)
+	}
+	return fileNames
+}
+
+func findFile(files []auroraconfig.AuroraConfigFile, fileName string) *auroraconfig.AuroraConfigFile {
+	for i := range files {
+		if files[i].Name == fileName {
+			return &files[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/skatteetaten/ao/pkg/auroraconfig"
+	"github.com/skatteetaten/ao/pkg/deploymentspec"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_getImpact(t *testing.T) {
+	refs, changes := getImpact(localFiles, localFiles, "about.json", ioutil.Discard)
+	assert.Equal(t, []string{"utv-relay/crm", "utv/crm"}, refs)
+	assert.Empty(t, changes)
+
+	refs, _ = getImpact(localFiles, localFiles, "utv/about.json", ioutil.Discard)
+	assert.Equal(t, []string{"utv/crm"}, refs)
+
+	after := withFile(localFiles, "about.json", `{"affiliation": "paas", "cluster": "test", "config": {"LOG_LEVEL": "WARN"}}`, true)
+	refs, changes = getImpact(localFiles, after, "about.json", ioutil.Discard)
+	assert.Equal(t, []string{"utv-relay/crm", "utv/crm"}, refs)
+	assert.Equal(t, []deploymentspec.RefChanges{
+		{Ref: "utv/crm", Changes: []auroraconfig.ValueChange{{Path: "/cluster", Before: `"utv"`, After: `"test"`}}},
+	}, changes)
+
+	header, rows := getSpecChangesTable(changes)
+	assert.Equal(t, "APPLICATION\tFIELD\tBEFORE\tAFTER", header)
+	assert.Equal(t, []string{"utv/crm\t/cluster\t\"utv\"\t\"test\""}, rows)
+
+	after = withFile(localFiles, "utv/billing.json", `{"baseFile": "crm", "replicas": 3}`, true)
+	refs, changes = getImpact(localFiles, after, "utv/billing.json", ioutil.Discard)
+	assert.Equal(t, []string{"utv/billing"}, refs)
+	assert.Equal(t, []deploymentspec.RefChanges{{Ref: "utv/billing", Added: true}}, changes)
+
+	_, rows = getSpecChangesTable(changes)
+	assert.Equal(t, []string{"utv/billing\t(added)\t\t"}, rows)
+}
+
+func Test_findImpactFile(t *testing.T) {
+	gitRoot, err := os.Getwd()
+	assert.NoError(t, err)
+
+	assert.Equal(t, "utv/about.json", findImpactFile(gitRoot, localFiles, filepath.Join("utv", "about.json")))
+	assert.Equal(t, "about.json", findImpactFile(gitRoot, localFiles, "about.json"))
+}
@@ -1,12 +1,25 @@
 package cmd
 
-import "github.com/spf13/cobra"
-import "strings"
-import "github.com/pkg/errors"
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/skatteetaten/ao/pkg/journal"
+	"github.com/spf13/cobra"
+)
+
+const exampleInspect = `  # Inspect a given deploy id
+  ao inspect 7f866742-6af7-47b1-b3a3-461fc4b948a4
+
+  # Inspect the latest deploy of foo/bar recorded in the local journal
+  ao inspect --last foo/bar`
+
+var flagLast bool
 
 var inspectCmd = &cobra.Command{
 	Use:         "inspect <deploy-id>",
 	Short:       "Inspect a given deploy id",
+	Example:     exampleInspect,
 	Annotations: map[string]string{"type": "remote"},
 	RunE:        inspect,
 }
@@ -14,6 +27,7 @@ var inspectCmd = &cobra.Command{
 func init() {
 	RootCmd.AddCommand(inspectCmd)
 	inspectCmd.Flags().StringVarP(&flagAuroraConfig, "auroraconfig", "a", "", "set auroraconfigId to which the deploy-id belongs to")
+	inspectCmd.Flags().BoolVarP(&flagLast, "last", "", false, "inspect the latest deploy of the given applicationDeploymentRef recorded in the local journal")
 }
 
 func inspect(cmd *cobra.Command, args []string) error {
@@ -24,10 +38,24 @@ func inspect(cmd *cobra.Command, args []string) error {
 	if flagAuroraConfig != "" {
 		DefaultApiClient.Affiliation = flagAuroraConfig
 	}
-	result, err := DefaultApiClient.GetApplyResult(args[0])
+
+	deployID := args[0]
+	if flagLast {
+		entry, err := deployJournal().Last(journal.Filter{
+			Affiliation: DefaultApiClient.Affiliation,
+			Search:      args[0],
+		})
+		if err != nil {
+			return err
+		}
+		cmd.Printf("Inspecting deploy of %s to %s at %s\n", entry.ApplicationDeploymentRef(), entry.Cluster, entry.Time.Local().Format("2006-01-02 15:04:05"))
+		deployID = entry.DeployId
+	}
+
+	result, err := DefaultApiClient.GetApplyResult(deployID)
 	if err != nil {
 		if strings.HasSuffix(err.Error(), "not found") {
-			return errors.Errorf("could not find deploy-id %s for AuroraConfig %s", args[0], DefaultApiClient.Affiliation)
+			return errors.Errorf("could not find deploy-id %s for AuroraConfig %s", deployID, DefaultApiClient.Affiliation)
 		}
 		return err
 	}
@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/skatteetaten/ao/pkg/auroraconfig"
+	"github.com/skatteetaten/ao/pkg/lint"
+	"github.com/spf13/cobra"
+)
+
+const lintLong = `Check the AuroraConfig in the current git repository without connecting to Boober.
+
+The following rules are checked:
+  syntax             JSON and YAML syntax errors
+  duplicate-key      keys defined more than once in the same object
+  unknown-field      top level fields that are not in the AuroraConfig schema
+  missing-base-file  ApplicationDeploymentRefs without a base application file
+  missing-env-about  environment folders without about.json
+  naming             environments and applications not named with lower case letters, digits and '-'
+
+The severity of a rule is error, warning or off. It is changed in ao-lint.json in the root of the
+AuroraConfig, or with --rule which takes precedence:
+  {"rules": {"naming": "error"}, "ignore": ["templates/*"]}
+
+The command fails if there are any errors. With --hook every problem is printed on a single line
+as file:line:column: severity: message (rule), e.g. for a git pre-push hook:
+  exec ao lint --hook`
+
+var (
+	flagLintRules []string
+	flagLintHook  bool
+)
+
+var lintCmd = &cobra.Command{
+	Use:         "lint",
+	Short:       "Check the local AuroraConfig for errors without connecting to Boober",
+	Long:        lintLong,
+	Annotations: map[string]string{"type": "local"},
+	RunE:        Lint,
+}
+
+func init() {
+	RootCmd.AddCommand(lintCmd)
+	lintCmd.Flags().StringArrayVarP(&flagLintRules, "rule", "", []string{}, "Severity of a rule in the form rule=error|warning|off")
+	lintCmd.Flags().BoolVarP(&flagLintHook, "hook", "", false, "Print one problem per line, suitable for git hooks and editors")
+	lintCmd.Flags().BoolVarP(&flagJSON, "json", "", false, "Print problems as json")
+}
+
+func Lint(cmd *cobra.Command, args []string) error {
+	rules, err := lint.ParseRules(flagLintRules)
+	if err != nil {
+		return err
+	}
+
+	ac, err := collectLocalAuroraConfig()
+	if err != nil {
+		return err
+	}
+
+	config, err := loadLintConfig(ac.Files, rules)
+	if err != nil {
+		return err
+	}
+
+	problems := lint.Lint(ac.Files, *config)
+	if err := printLintProblems(problems, cmd.OutOrStdout()); err != nil {
+		return err
+	}
+
+	if errorCount := problems.Count(lint.SeverityError); errorCount > 0 {
+		return errors.Errorf("AuroraConfig has %d lint error(s)", errorCount)
+	}
+	return nil
+}
+
+// loadLintConfig reads the lint configuration in the AuroraConfig, and overrides the rules with rules
+func loadLintConfig(files []auroraconfig.AuroraConfigFile, rules map[string]lint.Severity) (*lint.Config, error) {
+	config := &lint.Config{}
+	for _, file := range files {
+		if file.Name != lint.FileName {
+			continue
+		}
+
+		fromRepo, err := lint.Parse([]byte(file.Contents))
+		if err != nil {
+			return nil, errors.Wrapf(err, "Invalid %s in AuroraConfig", lint.FileName)
+		}
+		config = fromRepo
+	}
+
+	if len(rules) > 0 && config.Rules == nil {
+		config.Rules = make(map[string]lint.Severity)
+	}
+	for rule, severity := range rules {
+		config.Rules[rule] = severity
+	}
+
+	return config, nil
+}
+
+func printLintProblems(problems lint.Problems, out io.Writer) error {
+	if flagJSON {
+		if problems == nil {
+			problems = lint.Problems{}
+		}
+		data, err := json.MarshalIndent(problems, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(out, string(data))
+		return nil
+	}
+
+	if flagLintHook {
+		for _, problem := range problems {
+			fmt.Fprintln(out, problem.String())
+		}
+		return nil
+	}
+
+	if len(problems) == 0 {
+		fmt.Fprintln(out, "OK")
+		return nil
+	}
+
+	header, rows := getLintTable(problems)
+	DefaultTablePrinter(header, rows, out)
+	fmt.Fprintf(out, "\n%d error(s), %d warning(s)\n", problems.Count(lint.SeverityError), problems.Count(lint.SeverityWarning))
+	return nil
+}
+
+func getLintTable(problems lint.Problems) (string, []string) {
+	var rows []string
+	for _, problem := range problems {
+		severity := "\x1b[33mwarning\x1b[0m"
+		if problem.Severity == lint.SeverityError {
+			severity = "\x1b[31merror\x1b[0m"
+		}
+
+		var position []string
+		if problem.Line > 0 {
+			position = append(position, fmt.Sprint(problem.Line))
+		}
+		if problem.Column > 0 {
+			position = append(position, fmt.Sprint(problem.Column))
+		}
+
+		pattern := "%s\t%s\t%s\t%s\t%s"
+		rows = append(rows, fmt.Sprintf(pattern, severity, problem.File, strings.Join(position, ":"), problem.Rule, problem.Message))
+	}
+
+	header := "\x1b[00mSEVERITY\x1b[0m\tFILE\tLINE\tRULE\tMESSAGE"
+	return header, rows
+}
@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/skatteetaten/ao/pkg/auroraconfig"
+	"github.com/skatteetaten/ao/pkg/lint"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_loadLintConfig(t *testing.T) {
+	files := []auroraconfig.AuroraConfigFile{
+		{Name: "about.json", Contents: "{}"},
+		{Name: lint.FileName, Contents: `{"rules": {"naming": "error", "syntax": "warning"}}`},
+	}
+
+	config, err := loadLintConfig(files, map[string]lint.Severity{lint.RuleNaming: lint.SeverityOff})
+	assert.NoError(t, err)
+	assert.Equal(t, lint.SeverityOff, config.Severity(lint.RuleNaming))
+	assert.Equal(t, lint.SeverityWarning, config.Severity(lint.RuleSyntax))
+
+	config, err = loadLintConfig(files[:1], map[string]lint.Severity{lint.RuleNaming: lint.SeverityOff})
+	assert.NoError(t, err)
+	assert.Equal(t, lint.SeverityOff, config.Severity(lint.RuleNaming))
+
+	files[1].Contents = `{"rules": {"naming": "fatal"}}`
+	_, err = loadLintConfig(files, nil)
+	assert.Error(t, err)
+}
+
+func Test_getLintTable(t *testing.T) {
+	problems := lint.Problems{
+		{File: "utv/crm.json", Line: 3, Column: 5, Rule: lint.RuleDuplicateKey, Severity: lint.SeverityError, Message: "/version is already defined at line 2"},
+		{File: "Prod/crm.json", Rule: lint.RuleNaming, Severity: lint.SeverityWarning, Message: "environment Prod must consist of lower case letters, digits and '-'"},
+	}
+
+	header, rows := getLintTable(problems)
+
+	assert.Equal(t, "\x1b[00mSEVERITY\x1b[0m\tFILE\tLINE\tRULE\tMESSAGE", header)
+	assert.Equal(t, []string{
+		"\x1b[31merror\x1b[0m\tutv/crm.json\t3:5\tduplicate-key\t/version is already defined at line 2",
+		"\x1b[33mwarning\x1b[0m\tProd/crm.json\t\tnaming\tenvironment Prod must consist of lower case letters, digits and '-'",
+	}, rows)
+}
@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/skatteetaten/ao/pkg/client"
+	"github.com/skatteetaten/ao/pkg/notify"
+)
+
+var notificationClient = &http.Client{Timeout: 10 * time.Second}
+
+// notifyDeployResults sends the deploy outcome to the configured webhooks
+func notifyDeployResults(apiClient client.AuroraConfigClient, affiliation, refName string, results []client.DeployResults, errOut io.Writer) {
+	event := newEvent(time.Now(), currentUserName(), notify.EventDeploy, affiliation, refName, newDeployApplicationResults(results))
+	sendNotifications(apiClient, event, errOut)
+}
+
+// notifyDeleteResults sends the delete outcome to the configured webhooks
+func notifyDeleteResults(apiClient client.AuroraConfigClient, affiliation, refName string, results []partialDeleteResult, errOut io.Writer) {
+	event := newEvent(time.Now(), currentUserName(), notify.EventDelete, affiliation, refName, newDeleteApplicationResults(affiliation, results))
+	sendNotifications(apiClient, event, errOut)
+}
+
+// sendNotifications never fails the command, a failing webhook is only printed as a warning to errOut
+func sendNotifications(apiClient client.AuroraConfigClient, event notify.Event, errOut io.Writer) {
+	if len(event.Results) == 0 {
+		return
+	}
+
+	webhooks, err := loadWebhooks(apiClient, errOut)
+	if err != nil {
+		fmt.Fprintln(errOut, "Warning:", err)
+	}
+
+	for _, err := range webhooks.Send(notificationClient, event) {
+		fmt.Fprintln(errOut, "Warning:", err)
+	}
+}
+
+// loadWebhooks combines the webhooks in the local config with the notification file in the AuroraConfig
+func loadWebhooks(apiClient client.AuroraConfigClient, errOut io.Writer) (notify.Webhooks, error) {
+	var webhooks notify.Webhooks
+	if err := AO.Notifications.Validate(); err != nil {
+		fmt.Fprintln(errOut, "Warning:", errors.Wrap(err, "Invalid notifications in config"))
+	} else {
+		webhooks = append(webhooks, AO.Notifications...)
+	}
+
+	fileNames, err := apiClient.GetFileNames()
+	if err != nil {
+		return webhooks, err
+	}
+
+	if _, err := fileNames.Find(notify.FileName); err != nil {
+		return webhooks, nil
+	}
+
+	file, _, err := apiClient.GetAuroraConfigFile(notify.FileName)
+	if err != nil {
+		return webhooks, err
+	}
+
+	fromRepo, err := notify.Parse([]byte(file.Contents))
+	if err != nil {
+		return webhooks, errors.Wrapf(err, "Invalid %s in AuroraConfig", notify.FileName)
+	}
+
+	return append(webhooks, fromRepo...), nil
+}
+
+func newEvent(now time.Time, userName, eventType, affiliation, refName string, results []applicationResult) notify.Event {
+	event := notify.Event{
+		Type:        eventType,
+		Time:        now,
+		User:        userName,
+		Affiliation: affiliation,
+		RefName:     refName,
+	}
+
+	for _, result := range results {
+		event.Results = append(event.Results, notify.Result{
+			Cluster:     result.Cluster,
+			Environment: result.Environment,
+			Application: result.Application,
+			Version:     result.Version,
+			DeployId:    result.DeployId,
+			Success:     result.Success,
+			Reason:      result.Reason,
+			Warnings:    result.Warnings,
+		})
+	}
+
+	return event
+}
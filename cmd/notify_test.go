@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/skatteetaten/ao/pkg/client"
+	"github.com/skatteetaten/ao/pkg/deploymentspec"
+	"github.com/skatteetaten/ao/pkg/notify"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_newEvent(t *testing.T) {
+	t.Run("Should create a deploy event, and skip ignored applications", func(t *testing.T) {
+		results := []client.DeployResults{
+			{
+				Success: true,
+				Results: []client.DeployResult{
+					{DeployId: "abc", Success: true, Warnings: []string{"Config is deprecated"}, DeploymentSpec: deploymentspec.NewDeploymentSpec("crm", "dev", "east", "2")},
+					{DeployId: "-", Ignored: true, DeploymentSpec: deploymentspec.NewDeploymentSpec("erp", "dev", "east", "1")},
+				},
+			},
+		}
+
+		event := newEvent(time.Now(), "tester", notify.EventDeploy, "sales", "master", newDeployApplicationResults(results))
+
+		assert.Equal(t, notify.EventDeploy, event.Type)
+		assert.Equal(t, "tester", event.User)
+		assert.Equal(t, "sales", event.Affiliation)
+		assert.Equal(t, []notify.Result{
+			{Cluster: "east", Environment: "dev", Application: "crm", Version: "2", DeployId: "abc", Success: true, Warnings: []string{"Config is deprecated"}},
+		}, event.Results)
+	})
+
+	t.Run("Should create a delete event with the environment of the namespace", func(t *testing.T) {
+		partition := *newDeploymentPartition([]DeploymentInfo{*newDeploymentInfo("sales-dev", "crm", "east")}, *newTestCluster("east", true), "sales", "")
+		results := []partialDeleteResult{
+			newPartialDeleteResults(partition, client.DeleteResults{
+				Results: []client.DeleteResult{
+					{Success: false, Reason: "Forbidden", ApplicationRef: *client.NewApplicationRef("sales-dev", "crm")},
+				},
+			}),
+		}
+
+		event := newEvent(time.Now(), "tester", notify.EventDelete, "sales", "master", newDeleteApplicationResults("sales", results))
+
+		assert.Equal(t, notify.EventDelete, event.Type)
+		assert.False(t, event.Success())
+		assert.Equal(t, []notify.Result{
+			{Cluster: "east", Environment: "dev", Application: "crm", Version: "-", DeployId: "-", Success: false, Reason: "Forbidden"},
+		}, event.Results)
+	})
+}
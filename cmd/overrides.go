@@ -0,0 +1,242 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/skatteetaten/ao/pkg/auroraconfig"
+)
+
+const overrideFormat = "'[env/]file:{<json override>}' or '@<file with overrides>'"
+
+type overrideMap map[string]map[string]interface{}
+
+// overrideEntry is a single override of a file, as given on the command line
+type overrideEntry struct {
+	fileName string
+	value    map[string]interface{}
+}
+
+// overrideList keeps the overrides in the order they were given, so that later values win when they are merged
+type overrideList []overrideEntry
+
+// parseOverride collects overrides given as '[env/]file:{json}', '@file.json' and
+// '[env/]file:/json/path=value' in the order they are given. Overrides given with --set
+// come after the other overrides.
+func parseOverride(overrides, sets []string) (overrideList, error) {
+	var result overrideList
+
+	for _, override := range overrides {
+		if strings.HasPrefix(override, "@") {
+			fromFile, err := readOverrideFile(strings.TrimPrefix(override, "@"))
+			if err != nil {
+				return nil, err
+			}
+			for _, fileName := range fromFile.fileNames() {
+				result = append(result, overrideEntry{fileName: fileName, value: fromFile[fileName]})
+			}
+			continue
+		}
+
+		fileName, jsonOverride, err := splitOverride(override, overrideFormat)
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := parseOverrideObject(jsonOverride)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Invalid override for %s", fileName)
+		}
+
+		result = append(result, overrideEntry{fileName: fileName, value: value})
+	}
+
+	for _, set := range sets {
+		fileName, value, err := parseOverrideSet(set)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, overrideEntry{fileName: fileName, value: value})
+	}
+
+	return result, nil
+}
+
+// resolveFileNames replaces override file names with the matching file name in the AuroraConfig,
+// so that 'foo/bar' and 'foo/bar.json' refer to the same file, and merges the overrides in the order
+// they were given.
+func (o overrideList) resolveFileNames(fileNames auroraconfig.FileNames) (overrideMap, error) {
+	resolved := make(overrideMap)
+	for _, entry := range o {
+		fileName, err := fileNames.Find(entry.fileName)
+		if err != nil {
+			return nil, errors.Wrap(err, "Invalid override")
+		}
+		resolved.add(fileName, entry.value)
+	}
+
+	return resolved, nil
+}
+
+// toPayload returns the overrides in the format expected by Boober.
+func (o overrideMap) toPayload() (map[string]string, error) {
+	payload := make(map[string]string)
+	for fileName, value := range o {
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		payload[fileName] = string(data)
+	}
+
+	return payload, nil
+}
+
+func (o overrideMap) fileNames() []string {
+	var names []string
+	for name := range o {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// add merges value into the override of fileName. The value is copied, so later merges do not change it.
+func (o overrideMap) add(fileName string, value map[string]interface{}) {
+	if existing, ok := o[fileName]; ok {
+		mergeOverride(existing, value)
+		return
+	}
+	o[fileName] = copyOverride(value)
+}
+
+func getOverrideTable(overrides overrideMap) (string, []string) {
+	var rows []string
+	for _, fileName := range overrides.fileNames() {
+		data, _ := json.Marshal(overrides[fileName])
+		rows = append(rows, fmt.Sprintf("%s\t%s", fileName, data))
+	}
+
+	return "FILE\tOVERRIDE", rows
+}
+
+func splitOverride(override, format string) (string, string, error) {
+	index := strings.IndexByte(override, ':')
+	if index < 0 {
+		return "", "", errors.Errorf("Invalid override %s, must be in the form %s", override, format)
+	}
+
+	fileName := strings.TrimSpace(override[:index])
+	if fileName == "" {
+		return "", "", errors.Errorf("Invalid override %s, file name is missing", override)
+	}
+
+	return fileName, override[index+1:], nil
+}
+
+func parseOverrideObject(value string) (map[string]interface{}, error) {
+	if !json.Valid([]byte(value)) {
+		return nil, errors.Errorf("%s is not a valid json", value)
+	}
+
+	var object map[string]interface{}
+	if err := json.Unmarshal([]byte(value), &object); err != nil || object == nil {
+		return nil, errors.Errorf("%s is not a json object", value)
+	}
+
+	return object, nil
+}
+
+func parseOverrideSet(set string) (string, map[string]interface{}, error) {
+	const setFormat = "'[env/]file:/json/path=value'"
+
+	fileName, assignment, err := splitOverride(set, setFormat)
+	if err != nil {
+		return "", nil, err
+	}
+
+	index := strings.IndexByte(assignment, '=')
+	if index < 0 {
+		return "", nil, errors.Errorf("Invalid set %s, must be in the form %s", set, setFormat)
+	}
+
+	path, rawValue := assignment[:index], assignment[index+1:]
+	op := auroraconfig.JsonPatchOp{Path: path}
+	if err := op.Validate(); err != nil {
+		return "", nil, errors.Wrapf(err, "Invalid set %s", set)
+	}
+
+	var value interface{} = rawValue
+	var typed interface{}
+	if err := json.Unmarshal([]byte(rawValue), &typed); err == nil {
+		value = typed
+	}
+
+	segments := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	object := make(map[string]interface{})
+	current := object
+	for i, segment := range segments {
+		segment = strings.Replace(strings.Replace(segment, "~1", "/", -1), "~0", "~", -1)
+		if segment == "" {
+			return "", nil, errors.Errorf("Invalid set %s, json path contains an empty segment", set)
+		}
+		if i == len(segments)-1 {
+			current[segment] = value
+		} else {
+			next := make(map[string]interface{})
+			current[segment] = next
+			current = next
+		}
+	}
+
+	return fileName, object, nil
+}
+
+func readOverrideFile(path string) (overrideMap, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "Could not read overrides")
+	}
+
+	var fromFile overrideMap
+	if err := json.Unmarshal(data, &fromFile); err != nil {
+		return nil, errors.Errorf("%s must contain a json object with file names as keys and json objects as values", path)
+	}
+
+	for fileName, value := range fromFile {
+		if value == nil {
+			return nil, errors.Errorf("Override for %s in %s is not a json object", fileName, path)
+		}
+	}
+
+	return fromFile, nil
+}
+
+func mergeOverride(dst, src map[string]interface{}) {
+	for key, value := range src {
+		srcObject, srcIsObject := value.(map[string]interface{})
+		dstObject, dstIsObject := dst[key].(map[string]interface{})
+		if srcIsObject && dstIsObject {
+			mergeOverride(dstObject, srcObject)
+			continue
+		} else if srcIsObject {
+			value = copyOverride(srcObject)
+		}
+		dst[key] = value
+	}
+}
+
+func copyOverride(src map[string]interface{}) map[string]interface{} {
+	dst := make(map[string]interface{}, len(src))
+	for key, value := range src {
+		if object, isObject := value.(map[string]interface{}); isObject {
+			value = copyOverride(object)
+		}
+		dst[key] = value
+	}
+	return dst
+}
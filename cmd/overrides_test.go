@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/skatteetaten/ao/pkg/auroraconfig"
+	"github.com/stretchr/testify/assert"
+)
+
+var testOverrideFileNames = auroraconfig.FileNames{"about.json", "foo/bar.json"}
+
+func Test_parseOverride(t *testing.T) {
+	t.Run("Should parse and merge overrides for the same file", func(t *testing.T) {
+		overrides, err := parseOverride([]string{
+			`foo/bar.json:{"pause": true, "config": {"A": "1"}}`,
+			`foo/bar.json:{"config": {"B": "2"}}`,
+			`about.json:{"cluster": "utv"}`,
+		}, nil)
+
+		assert.NoError(t, err)
+		resolved, err := overrides.resolveFileNames(testOverrideFileNames)
+		assert.NoError(t, err)
+		payload, err := resolved.toPayload()
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"pause": true, "config": {"A": "1", "B": "2"}}`, payload["foo/bar.json"])
+		assert.JSONEq(t, `{"cluster": "utv"}`, payload["about.json"])
+	})
+
+	t.Run("Should compile set expressions into overrides", func(t *testing.T) {
+		overrides, err := parseOverride([]string{`foo/bar.json:{"config": {"A": "1"}}`}, []string{
+			"foo/bar.json:/config/B=2",
+			"foo/bar.json:/pause=true",
+			"foo/bar.json:/version=1.2.3",
+			"foo/bar.json:/config/a~1b=x=y",
+		})
+
+		assert.NoError(t, err)
+		resolved, err := overrides.resolveFileNames(testOverrideFileNames)
+		assert.NoError(t, err)
+		payload, err := resolved.toPayload()
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"pause": true, "version": "1.2.3", "config": {"A": "1", "B": 2, "a/b": "x=y"}}`, payload["foo/bar.json"])
+	})
+
+	t.Run("Should read overrides from file", func(t *testing.T) {
+		file, err := ioutil.TempFile("", "ao_overrides")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.Remove(file.Name())
+		file.WriteString(`{"foo/bar.json": {"pause": true}}`)
+		file.Close()
+
+		overrides, err := parseOverride([]string{"@" + file.Name(), `foo/bar.json:{"replicas": 2}`}, nil)
+		assert.NoError(t, err)
+		resolved, err := overrides.resolveFileNames(testOverrideFileNames)
+		assert.NoError(t, err)
+		payload, err := resolved.toPayload()
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"pause": true, "replicas": 2}`, payload["foo/bar.json"])
+	})
+
+	t.Run("Should fail on malformed overrides", func(t *testing.T) {
+		invalid := [][]string{
+			{`foo/bar.json{"pause": true}`},
+			{`:{"pause": true}`},
+			{`foo/bar.json:{"pause": true`},
+			{`foo/bar.json:[1, 2]`},
+			{`foo/bar.json:null`},
+			{"@/does/not/exist.json"},
+		}
+		for _, overrides := range invalid {
+			_, err := parseOverride(overrides, nil)
+			assert.Error(t, err, overrides[0])
+		}
+
+		invalidSets := []string{"foo/bar.json", "foo/bar.json:/pause", "foo/bar.json:pause=true", "foo/bar.json:/config//A=1"}
+		for _, set := range invalidSets {
+			_, err := parseOverride(nil, []string{set})
+			assert.Error(t, err, set)
+		}
+	})
+}
+
+func Test_resolveOverrideFileNames(t *testing.T) {
+	t.Run("Should merge overrides of the same file", func(t *testing.T) {
+		overrides, err := parseOverride([]string{`foo/bar:{"pause": true}`, `foo/bar.json:{"replicas": 2}`}, nil)
+		assert.NoError(t, err)
+
+		resolved, err := overrides.resolveFileNames(testOverrideFileNames)
+		assert.NoError(t, err)
+		assert.Len(t, resolved, 1)
+		assert.Equal(t, map[string]interface{}{"pause": true, "replicas": float64(2)}, resolved["foo/bar.json"])
+
+		_, err = overrides.resolveFileNames(auroraconfig.FileNames{"about.json"})
+		assert.Error(t, err)
+	})
+
+	t.Run("Should merge overrides in the order they are given", func(t *testing.T) {
+		overrides, err := parseOverride([]string{`foo/bar.json:{"replicas": 1}`, `foo/bar:{"replicas": 2}`}, nil)
+		assert.NoError(t, err)
+
+		resolved, err := overrides.resolveFileNames(testOverrideFileNames)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{"replicas": float64(2)}, resolved["foo/bar.json"])
+	})
+
+	t.Run("Should not change the given overrides when merging", func(t *testing.T) {
+		overrides, err := parseOverride([]string{`foo/bar:{"config": {"A": "1"}}`, `foo/bar.json:{"config": {"B": "2"}}`}, nil)
+		assert.NoError(t, err)
+
+		_, err = overrides.resolveFileNames(testOverrideFileNames)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{"config": map[string]interface{}{"A": "1"}}, overrides[0].value)
+		assert.Equal(t, map[string]interface{}{"config": map[string]interface{}{"B": "2"}}, overrides[1].value)
+	})
+}
+
+func Test_getOverrideTable(t *testing.T) {
+	overrides, err := parseOverride([]string{`foo/bar.json:{"pause": true}`, `about.json:{"cluster": "utv"}`}, nil)
+	assert.NoError(t, err)
+	resolved, err := overrides.resolveFileNames(testOverrideFileNames)
+	assert.NoError(t, err)
+
+	header, rows := getOverrideTable(resolved)
+	assert.Equal(t, "FILE\tOVERRIDE", header)
+	assert.Equal(t, []string{"about.json\t{\"cluster\":\"utv\"}", "foo/bar.json\t{\"pause\":true}"}, rows)
+}
@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/skatteetaten/ao/pkg/auroraconfig"
+	"github.com/skatteetaten/ao/pkg/service"
+	"github.com/spf13/cobra"
+)
+
+const patchLong = `Apply a patch to a single file in the current AuroraConfig.
+By default the patch is a RFC 6902 JSON patch, an array of add, remove, replace, move, copy and test operations
+applied in one request. If any operation fails, e.g. a test operation, the file is left unchanged.
+
+With --merge the patch is a RFC 7386 merge patch, a JSON object merged into the file where null removes a field.
+The merge is computed locally and rejected if the file is changed by someone else before it is applied.
+Merge patches are only supported for JSON files.`
+
+const patchExample = `  # ops.json
+  [
+    {"op": "test", "path": "/version", "value": "1.2.3"},
+    {"op": "replace", "path": "/version", "value": "1.2.4"},
+    {"op": "move", "from": "/config/OLD_NAME", "path": "/config/NEW_NAME"}
+  ]
+  ao patch test/foo.json -f ops.json
+
+  # Read the patch from stdin
+  echo '[{"op": "copy", "from": "/route", "path": "/routeDefaults"}]' | ao patch test/foo -f -
+
+  # Set replicas and remove pause
+  echo '{"replicas": 2, "pause": null}' | ao patch test/foo --merge -f -`
+
+var (
+	flagPatchFile  string
+	flagPatchMerge bool
+)
+
+var patchCmd = &cobra.Command{
+	Use:         "patch <file> -f <patch-file>",
+	Short:       "Apply a JSON patch or merge patch to a file in the current AuroraConfig",
+	Long:        patchLong,
+	Annotations: map[string]string{"type": "remote"},
+	Example:     patchExample,
+	RunE:        Patch,
+}
+
+func init() {
+	RootCmd.AddCommand(patchCmd)
+	patchCmd.Flags().StringVarP(&flagPatchFile, "file", "f", "", "File containing the patch, - reads from stdin")
+	patchCmd.Flags().BoolVarP(&flagPatchMerge, "merge", "", false, "Read the patch as a RFC 7386 merge patch")
+}
+
+func Patch(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 || flagPatchFile == "" {
+		return cmd.Usage()
+	}
+
+	data, err := readPatchFile(flagPatchFile)
+	if err != nil {
+		return err
+	}
+
+	if flagPatchMerge {
+		fileName, patch, err := service.MergePatchFile(DefaultApiClient, args[0], data)
+		if err != nil {
+			return err
+		}
+
+		if len(patch) == 0 {
+			cmd.Printf("%s is unchanged\n", fileName)
+			return nil
+		}
+
+		cmd.Printf("%s has been updated\n%s\n", fileName, patch)
+		return nil
+	}
+
+	patch, err := auroraconfig.ParseJsonPatch(data)
+	if err != nil {
+		return errors.Wrap(err, flagPatchFile)
+	}
+
+	fileName, err := service.PatchFile(DefaultApiClient, args[0], patch)
+	if err != nil {
+		return err
+	}
+
+	cmd.Printf("%s has been updated\n", fileName)
+
+	return nil
+}
+
+func readPatchFile(path string) ([]byte, error) {
+	if path == "-" {
+		return ioutil.ReadAll(os.Stdin)
+	}
+	return ioutil.ReadFile(path)
+}
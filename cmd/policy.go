@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/skatteetaten/ao/pkg/auroraconfig"
+	"github.com/skatteetaten/ao/pkg/client"
+	"github.com/skatteetaten/ao/pkg/journal"
+	"github.com/skatteetaten/ao/pkg/policy"
+)
+
+var flagOverridePolicy string
+
+// policyRefName is the git ref the policy file is always read from, so that deploying from another ref
+// can not remove or weaken the policies
+const policyRefName = "master"
+
+// policyRefClient returns a copy of apiClient reading the AuroraConfig at policyRefName
+func policyRefClient(apiClient *client.ApiClient) *client.ApiClient {
+	policyClient := *apiClient
+	policyClient.RefName = policyRefName
+	return &policyClient
+}
+
+// loadDeployPolicies combines the policies in the local config with the policy file in the AuroraConfig.
+// The policy file is read from policyClient at the default ref. A policy file that is changed in the
+// deployed ref only adds its policies, fileNames are the files of the deployed ref.
+func loadDeployPolicies(apiClient, policyClient client.AuroraConfigClient, fileNames auroraconfig.FileNames) (policy.Policies, error) {
+	policies := append(policy.Policies{}, AO.DeployPolicies...)
+	if err := policies.Validate(); err != nil {
+		return nil, errors.Wrap(err, "Invalid deployPolicies in config")
+	}
+
+	policyFileNames, err := policyClient.GetFileNames()
+	if err != nil {
+		return nil, err
+	}
+
+	defaultContents, err := readPolicyFile(policyClient, policyFileNames)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Unable to read %s in %s", policy.FileName, policyRefName)
+	}
+	contents, err := readPolicyFile(apiClient, fileNames)
+	if err != nil {
+		return nil, err
+	}
+
+	policyFiles := []string{defaultContents}
+	if contents != defaultContents {
+		policyFiles = append(policyFiles, contents)
+	}
+
+	for _, policyFile := range policyFiles {
+		if policyFile == "" {
+			continue
+		}
+		fromRepo, err := policy.Parse([]byte(policyFile))
+		if err != nil {
+			return nil, errors.Wrapf(err, "Invalid %s in AuroraConfig", policy.FileName)
+		}
+		policies = append(policies, fromRepo...)
+	}
+
+	return policies, nil
+}
+
+// readPolicyFile returns the contents of the policy file, or an empty string if there is none
+func readPolicyFile(apiClient client.AuroraConfigClient, fileNames auroraconfig.FileNames) (string, error) {
+	if _, err := fileNames.Find(policy.FileName); err != nil {
+		return "", nil
+	}
+
+	file, _, err := apiClient.GetAuroraConfigFile(policy.FileName)
+	if err != nil {
+		return "", err
+	}
+	return file.Contents, nil
+}
+
+// enforceDeployPolicies prints the violations and fails unless the policies are explicitly overridden.
+// Overrides are recorded in the journal, and a warning is printed to errOut.
+func enforceDeployPolicies(report *policy.Report, overrideReason, affiliation, refName string, out, errOut io.Writer) error {
+	if len(report.Violations) == 0 {
+		return nil
+	}
+
+	fmt.Fprintln(out, "Deploy violates one or more policies:")
+	header, rows := getPolicyViolationTable(report.Violations)
+	DefaultTablePrinter(header, rows, out)
+	fmt.Fprintln(out, "")
+
+	if overrideReason == "" {
+		return errors.New("Deploy stopped by policy, use --override-policy <reason> to deploy anyway")
+	}
+
+	fmt.Fprintf(errOut, "Warning: Overriding %d policy violation(s): %s\n", len(report.Violations), overrideReason)
+	entries := newPolicyOverrideJournalEntries(time.Now(), currentUserName(), affiliation, refName, overrideReason, report.Violations)
+	if err := deployJournal().Append(entries...); err != nil {
+		return errors.Wrap(err, "Policy override could not be recorded")
+	}
+
+	return nil
+}
+
+// confirmProtectedEnvironments requires the name of every protected environment to be typed
+func confirmProtectedEnvironments(environments []string, input func(message string) string) error {
+	for _, env := range environments {
+		message := fmt.Sprintf("%s is a protected environment. Type the environment name to continue:", env)
+		if strings.TrimSpace(input(message)) != env {
+			return errors.Errorf("Confirmation did not match %s, nothing was deployed", env)
+		}
+	}
+	return nil
+}
+
+func getPolicyViolationTable(violations []policy.Violation) (string, []string) {
+	var rows []string
+	for _, violation := range violations {
+		pattern := "%s\t%s\t%s\t%s\t%s"
+		rows = append(rows, fmt.Sprintf(pattern, violation.Policy, violation.Cluster, violation.Environment, violation.Application, violation.Message))
+	}
+
+	header := "POLICY\tCLUSTER\tENVIRONMENT\tAPPLICATION\tVIOLATION"
+	return header, rows
+}
+
+func newPolicyOverrideJournalEntries(now time.Time, userName, affiliation, refName, reason string, violations []policy.Violation) []journal.Entry {
+	var entries []journal.Entry
+	for _, violation := range violations {
+		entries = append(entries, journal.Entry{
+			Time:        now,
+			User:        userName,
+			Action:      journal.ActionPolicyOverride,
+			Affiliation: affiliation,
+			RefName:     refName,
+			Cluster:     violation.Cluster,
+			Environment: violation.Environment,
+			Application: violation.Application,
+			Version:     "-",
+			DeployId:    "-",
+			Status:      journal.StatusOverridden,
+			Reason:      fmt.Sprintf("%s: %s (%s)", violation.Policy, violation.Message, reason),
+		})
+	}
+	return entries
+}
@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/skatteetaten/ao/pkg/auroraconfig"
+	"github.com/skatteetaten/ao/pkg/client"
+	"github.com/skatteetaten/ao/pkg/journal"
+	"github.com/skatteetaten/ao/pkg/policy"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_loadDeployPolicies(t *testing.T) {
+	AO = GetDefaultAOConfig()
+	AO.DeployPolicies = policy.Policies{{Name: "local", Clusters: []string{"prod"}, Protected: true}}
+
+	apiClient := client.NewAuroraConfigClientMock([]string{"about.json", "foo/bar.json"})
+	policies, err := loadDeployPolicies(apiClient, apiClient, auroraconfig.FileNames{"about.json", "foo/bar.json"})
+
+	assert.NoError(t, err)
+	assert.Len(t, policies, 1)
+	assert.Equal(t, "local", policies[0].Name)
+
+	AO.DeployPolicies = policy.Policies{{DeployHours: "always"}}
+	_, err = loadDeployPolicies(apiClient, apiClient, auroraconfig.FileNames{"about.json"})
+	assert.Error(t, err)
+}
+
+func Test_loadDeployPolicies_fromDefaultRef(t *testing.T) {
+	AO = GetDefaultAOConfig()
+
+	masterClient := client.NewAuroraConfigClientMock([]string{"about.json", policy.FileName})
+	masterClient.Files = map[string]string{policy.FileName: `{"policies": [{"name": "prod", "environments": ["prod.*"], "protected": true}]}`}
+
+	t.Run("Should use the policies of the default ref when the deployed ref removes them", func(t *testing.T) {
+		branchClient := client.NewAuroraConfigClientMock([]string{"about.json"})
+
+		policies, err := loadDeployPolicies(branchClient, masterClient, auroraconfig.FileNames{"about.json"})
+
+		assert.NoError(t, err)
+		if assert.Len(t, policies, 1) {
+			assert.Equal(t, "prod", policies[0].Name)
+		}
+	})
+
+	t.Run("Should add the policies of the deployed ref when they are changed", func(t *testing.T) {
+		branchClient := client.NewAuroraConfigClientMock([]string{"about.json", policy.FileName})
+		branchClient.Files = map[string]string{policy.FileName: `{"policies": [{"name": "test", "environments": ["test.*"], "protected": false}]}`}
+
+		policies, err := loadDeployPolicies(branchClient, masterClient, auroraconfig.FileNames{"about.json", policy.FileName})
+
+		assert.NoError(t, err)
+		if assert.Len(t, policies, 2) {
+			assert.Equal(t, "prod", policies[0].Name)
+			assert.True(t, policies[0].Protected)
+			assert.Equal(t, "test", policies[1].Name)
+		}
+	})
+
+	t.Run("Should not repeat unchanged policies", func(t *testing.T) {
+		policies, err := loadDeployPolicies(masterClient, masterClient, auroraconfig.FileNames{"about.json", policy.FileName})
+
+		assert.NoError(t, err)
+		assert.Len(t, policies, 1)
+	})
+}
+
+func Test_confirmProtectedEnvironments(t *testing.T) {
+	answers := map[string]string{}
+	input := func(message string) string {
+		for env, answer := range answers {
+			if message == env+" is a protected environment. Type the environment name to continue:" {
+				return answer
+			}
+		}
+		return ""
+	}
+
+	answers["prod"] = "prod "
+	answers["prod-relay"] = "prod-relay"
+	assert.NoError(t, confirmProtectedEnvironments([]string{"prod", "prod-relay"}, input))
+
+	answers["prod-relay"] = "prod"
+	assert.Error(t, confirmProtectedEnvironments([]string{"prod", "prod-relay"}, input))
+}
+
+func Test_getPolicyViolationTable(t *testing.T) {
+	violations := []policy.Violation{
+		{Policy: "production", Cluster: "prod", Environment: "prod", Application: "crm", Message: "Overrides are not allowed"},
+	}
+
+	header, rows := getPolicyViolationTable(violations)
+	assert.Equal(t, "POLICY\tCLUSTER\tENVIRONMENT\tAPPLICATION\tVIOLATION", header)
+	assert.Equal(t, []string{"production\tprod\tprod\tcrm\tOverrides are not allowed"}, rows)
+}
+
+func Test_newPolicyOverrideJournalEntries(t *testing.T) {
+	violations := []policy.Violation{
+		{Policy: "production", Cluster: "prod", Environment: "prod", Application: "crm", Message: "Overrides are not allowed"},
+	}
+
+	entries := newPolicyOverrideJournalEntries(time.Now(), "tester", "sales", "master", "Hotfix", violations)
+
+	assert.Len(t, entries, 1)
+	assert.Equal(t, journal.ActionPolicyOverride, entries[0].Action)
+	assert.Equal(t, journal.StatusOverridden, entries[0].Status)
+	assert.Equal(t, "prod/crm", entries[0].ApplicationDeploymentRef())
+	assert.Equal(t, "production: Overrides are not allowed (Hotfix)", entries[0].Reason)
+}
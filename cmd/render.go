@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/skatteetaten/ao/pkg/auroraconfig"
+	"github.com/skatteetaten/ao/pkg/deploymentspec"
+	"github.com/skatteetaten/ao/pkg/versioncontrol"
+	"github.com/spf13/cobra"
+)
+
+const renderLong = `Merge the files of an application in the local AuroraConfig and print the resulting configuration.
+
+The files are merged like Boober does, the most specific file wins:
+  about.json, <app>.json, <env>/about.json, <env>/<app>.json
+baseFile and envFile in <env>/<app>.json replace <app>.json and <env>/about.json.
+
+Every field is printed with the file it came from. This is only an approximation of the deployment spec
+made by Boober, default values are not added and the result is not validated. Use 'ao get spec' for the
+deployment spec of the committed AuroraConfig.`
+
+var renderCmd = &cobra.Command{
+	Use:         "render <env/app>",
+	Short:       "Print the merged configuration of an application from the local AuroraConfig",
+	Long:        renderLong,
+	Annotations: map[string]string{"type": "local"},
+	RunE:        Render,
+}
+
+func init() {
+	RootCmd.AddCommand(renderCmd)
+	renderCmd.Flags().BoolVarP(&flagJSON, "json", "", false, "Print the merged configuration as json, with the sources of every field")
+}
+
+func Render(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return cmd.Usage()
+	}
+
+	ac, err := collectLocalAuroraConfig()
+	if err != nil {
+		return err
+	}
+
+	spec, err := renderLocalSpec(ac.Files, args[0])
+	if err != nil {
+		return err
+	}
+
+	if flagJSON {
+		data, err := json.MarshalIndent(spec, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), string(data))
+		return nil
+	}
+
+	header, rows := getRenderTable(spec)
+	DefaultTablePrinter(header, rows, cmd.OutOrStdout())
+	return nil
+}
+
+// collectLocalAuroraConfig reads the AuroraConfig in the git repository of the working directory
+func collectLocalAuroraConfig() (*auroraconfig.AuroraConfig, error) {
+	gitRoot, err := localGitRoot()
+	if err != nil {
+		return nil, err
+	}
+
+	return versioncontrol.CollectAuroraConfigFilesInRepo(DefaultApiClient.Affiliation, gitRoot)
+}
+
+func localGitRoot() (string, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+
+	return versioncontrol.FindGitPath(wd)
+}
+
+// renderLocalSpec renders the deployment spec of the single ApplicationDeploymentRef matching search
+func renderLocalSpec(files []auroraconfig.AuroraConfigFile, search string) (deploymentspec.DeploymentSpec, error) {
+	ref, err := findApplicationDeploymentRef(search, fileNamesOf(files))
+	if err != nil {
+		return nil, err
+	}
+
+	return deploymentspec.NewRenderer(files).Render(ref)
+}
+
+// findApplicationDeploymentRef returns the single ApplicationDeploymentRef matching search
+func findApplicationDeploymentRef(search string, fileNames auroraconfig.FileNames) (string, error) {
+	matches := auroraconfig.FindMatches(search, fileNames.GetApplicationDeploymentRefs(), false)
+	if len(matches) == 0 {
+		return "", errors.Errorf("No matches for %s", search)
+	} else if len(matches) > 1 {
+		return "", errors.Errorf("Search matched more than one file. Search must be more specific.\n%v", matches)
+	}
+	return matches[0], nil
+}
+
+func getRenderTable(spec deploymentspec.DeploymentSpec) (string, []string) {
+	var rows []string
+	for _, field := range spec.Fields() {
+		rows = append(rows, fmt.Sprintf("%s\t%s\t%s", field, formatSpecValue(spec.Get(field)), spec.Source(field)))
+	}
+
+	header := "FIELD\tVALUE\tSOURCE"
+	return header, rows
+}
+
+// formatSpecValue prints strings as they are and other values as json
+func formatSpecValue(value interface{}) string {
+	if text, ok := value.(string); ok {
+		return text
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
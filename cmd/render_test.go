@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/skatteetaten/ao/pkg/auroraconfig"
+	"github.com/stretchr/testify/assert"
+)
+
+var localFiles = []auroraconfig.AuroraConfigFile{
+	{Name: "about.json", Contents: `{"affiliation": "paas", "cluster": "utv"}`},
+	{Name: "crm.json", Contents: `{"version": "1.0.0", "route": true, "config": {"LOG_LEVEL": "INFO"}}`},
+	{Name: "utv/about.json", Contents: `{}`},
+	{Name: "utv/crm.json", Contents: `{"replicas": 2, "config": {"LOG_LEVEL": "DEBUG"}}`},
+	{Name: "utv-relay/about.json", Contents: `{"cluster": "utv-relay"}`},
+	{Name: "utv-relay/crm.json", Contents: `{"version": "1.1.0"}`},
+}
+
+func Test_renderLocalSpec(t *testing.T) {
+	spec, err := renderLocalSpec(localFiles, "utv/crm")
+	assert.NoError(t, err)
+
+	header, rows := getRenderTable(spec)
+	assert.Equal(t, "FIELD\tVALUE\tSOURCE", header)
+	assert.Equal(t, []string{
+		"/affiliation\tpaas\tabout.json",
+		"/applicationDeploymentRef\tutv/crm\tstatic",
+		"/cluster\tutv\tabout.json",
+		"/config/LOG_LEVEL\tDEBUG\tutv/crm.json",
+		"/envName\tutv\tfolderName",
+		"/name\tcrm\tfileName",
+		"/replicas\t2\tutv/crm.json",
+		"/route\ttrue\tcrm.json",
+		"/version\t1.0.0\tcrm.json",
+	}, rows)
+
+	_, err = renderLocalSpec(localFiles, "crm")
+	assert.Error(t, err)
+
+	_, err = renderLocalSpec(localFiles, "prod/crm")
+	assert.EqualError(t, err, "No matches for prod/crm")
+}
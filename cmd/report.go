@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/skatteetaten/ao/pkg/client"
+	"github.com/skatteetaten/ao/pkg/report"
+)
+
+const reportUsage = "Write a report of the result, junit=path or markdown=path. May be repeated"
+
+var flagReports []string
+
+func newDeployReport(affiliation string, results []client.DeployResults) report.Suite {
+	return newResultReport("deploy", affiliation, newDeployApplicationResults(results))
+}
+
+func newDeleteReport(affiliation string, results []partialDeleteResult) report.Suite {
+	return newResultReport("delete", affiliation, newDeleteApplicationResults(affiliation, results))
+}
+
+// newResultReport creates a test case per application, grouped by cluster and environment
+func newResultReport(command, affiliation string, results []applicationResult) report.Suite {
+	suite := report.Suite{Name: fmt.Sprintf("ao %s %s", command, affiliation), Time: time.Now()}
+	for _, result := range results {
+		name := result.Application
+		if result.Version != "-" {
+			name = fmt.Sprintf("%s (%s)", result.Application, result.Version)
+		}
+
+		testCase := report.TestCase{
+			ClassName: fmt.Sprintf("%s.%s", result.Cluster, result.Environment),
+			Name:      name,
+			Warnings:  result.Warnings,
+		}
+		if !result.Success {
+			testCase.Failure = failureReason(result.Reason)
+		}
+		suite.Cases = append(suite.Cases, testCase)
+	}
+	return suite
+}
+
+// newValidationReport creates a test case per validation error. A successful validation is a single
+// passing test case with the warnings from Boober.
+func newValidationReport(affiliation, warnings string, validationErr error) report.Suite {
+	suite := report.Suite{Name: fmt.Sprintf("ao validate %s", affiliation), Time: time.Now()}
+
+	if errorResponse, ok := validationErr.(*client.ErrorResponse); ok && len(errorResponse.Details) > 0 {
+		for _, detail := range errorResponse.Details {
+			suite.Cases = append(suite.Cases, report.TestCase{
+				ClassName: detail.Environment,
+				Name:      detail.Application,
+				Failure:   detail.Formatted,
+			})
+		}
+		return suite
+	}
+
+	testCase := report.TestCase{ClassName: affiliation, Name: "AuroraConfig"}
+	if validationErr != nil {
+		testCase.Failure = validationErr.Error()
+	} else if warnings != "" {
+		testCase.Warnings = []string{warnings}
+	}
+	suite.Cases = append(suite.Cases, testCase)
+
+	return suite
+}
+
+func failureReason(reason string) string {
+	if reason == "" {
+		return "Failed"
+	}
+	return reason
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/skatteetaten/ao/pkg/client"
+	"github.com/skatteetaten/ao/pkg/deploymentspec"
+	"github.com/skatteetaten/ao/pkg/report"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_newDeployReport(t *testing.T) {
+	results := []client.DeployResults{
+		{
+			Success: true,
+			Results: []client.DeployResult{
+				{DeployId: "abc", Success: true, Warnings: []string{"Config is deprecated"}, DeploymentSpec: deploymentspec.NewDeploymentSpec("crm", "dev", "east", "2")},
+				{DeployId: "-", Ignored: true, DeploymentSpec: deploymentspec.NewDeploymentSpec("erp", "dev", "east", "1")},
+			},
+		},
+		errorDeployResults("Cluster is not reachable", *newDeploySpecPartition(testSpecs[11:12], *newTestCluster("north", false), "sales", "")),
+	}
+
+	suite := newDeployReport("sales", results)
+
+	assert.Equal(t, "ao deploy sales", suite.Name)
+	assert.Len(t, suite.Cases, 2)
+	assert.Equal(t, report.TestCase{ClassName: "east.dev", Name: "crm (2)", Warnings: []string{"Config is deprecated"}}, suite.Cases[0])
+	assert.Equal(t, "north.--prod", suite.Cases[1].ClassName)
+	assert.Equal(t, "Cluster is not reachable", suite.Cases[1].Failure)
+}
+
+func Test_newDeleteReport(t *testing.T) {
+	partition := *newDeploymentPartition([]DeploymentInfo{*newDeploymentInfo("sales-dev", "crm", "east")}, *newTestCluster("east", true), "sales", "")
+	results := []partialDeleteResult{
+		newPartialDeleteResults(partition, client.DeleteResults{
+			Results: []client.DeleteResult{
+				{Success: false, ApplicationRef: *client.NewApplicationRef("sales-dev", "crm")},
+			},
+		}),
+	}
+
+	suite := newDeleteReport("sales", results)
+
+	assert.Equal(t, []report.TestCase{{ClassName: "east.dev", Name: "crm", Failure: "Failed"}}, suite.Cases)
+}
+
+func Test_newValidationReport(t *testing.T) {
+	errorResponse := &client.ErrorResponse{
+		ContainsError: true,
+		Details: []client.ErrorDetail{
+			{Environment: "dev", Application: "crm", Type: "MISSING", Message: "Version must be set", Formatted: "Application: dev/crm\nField:       version (Missing)"},
+		},
+	}
+
+	suite := newValidationReport("sales", "", errorResponse)
+	assert.Equal(t, []report.TestCase{{ClassName: "dev", Name: "crm", Failure: "Application: dev/crm\nField:       version (Missing)"}}, suite.Cases)
+
+	suite = newValidationReport("sales", "Application: dev/crm\nWarning:     deprecated", nil)
+	assert.Equal(t, []report.TestCase{{ClassName: "sales", Name: "AuroraConfig", Warnings: []string{"Application: dev/crm\nWarning:     deprecated"}}}, suite.Cases)
+
+	suite = newValidationReport("sales", "", errors.New("Service unavailable"))
+	assert.Equal(t, 1, suite.Failures())
+}
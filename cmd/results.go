@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/skatteetaten/ao/pkg/client"
+)
+
+// applicationResult is the outcome of deploying or deleting one application. The journal, notifications
+// and reports are all built from it, so that they describe an application the same way.
+type applicationResult struct {
+	Cluster     string
+	Environment string
+	Application string
+	Version     string
+	DeployId    string
+	Success     bool
+	Reason      string
+	Warnings    []string
+}
+
+// newDeployApplicationResults flattens deploy results, ignored applications are left out
+func newDeployApplicationResults(results []client.DeployResults) []applicationResult {
+	var applicationResults []applicationResult
+	for _, result := range results {
+		for _, deploy := range result.Results {
+			if deploy.Ignored {
+				continue
+			}
+
+			applicationResults = append(applicationResults, applicationResult{
+				Cluster:     deploy.DeploymentSpec.Cluster(),
+				Environment: deploy.DeploymentSpec.Environment(),
+				Application: deploy.DeploymentSpec.Name(),
+				Version:     deploy.DeploymentSpec.Version(),
+				DeployId:    deploy.DeployId,
+				Success:     deploy.Success,
+				Reason:      deploy.Reason,
+				Warnings:    deploy.Warnings,
+			})
+		}
+	}
+	return applicationResults
+}
+
+// newDeleteApplicationResults flattens delete results. Boober only returns the namespace of a deleted
+// application, the environment is the namespace without the affiliation prefix.
+func newDeleteApplicationResults(affiliation string, results []partialDeleteResult) []applicationResult {
+	var applicationResults []applicationResult
+	for _, result := range results {
+		for _, deleteResult := range result.deleteResults.Results {
+			applicationResults = append(applicationResults, applicationResult{
+				Cluster:     result.partition.Cluster.Name,
+				Environment: strings.TrimPrefix(deleteResult.ApplicationRef.Namespace, affiliation+"-"),
+				Application: deleteResult.ApplicationRef.Name,
+				Version:     "-",
+				DeployId:    "-",
+				Success:     deleteResult.Success,
+				Reason:      deleteResult.Reason,
+			})
+		}
+	}
+	return applicationResults
+}
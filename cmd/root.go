@@ -67,6 +67,8 @@ var (
 	DefaultApiClient *client.ApiClient
 	AO               *config.AOConfig
 	ConfigLocation   string
+	// ConfigDir holds local state such as the deploy journal
+	ConfigDir string
 )
 
 var RootCmd = &cobra.Command{
@@ -101,6 +103,7 @@ func initialize(cmd *cobra.Command, args []string) error {
 		return err
 	}
 	ConfigLocation = filepath.Join(home, ".ao.json")
+	ConfigDir = filepath.Join(home, ".ao")
 
 	err = setLogging(pFlagLogLevel, pFlagPrettyLog)
 	if err != nil {
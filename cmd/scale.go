@@ -0,0 +1,261 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/skatteetaten/ao/pkg/auroraconfig"
+	"github.com/skatteetaten/ao/pkg/client"
+	"github.com/skatteetaten/ao/pkg/deploymentspec"
+	"github.com/skatteetaten/ao/pkg/policy"
+	"github.com/skatteetaten/ao/pkg/prompt"
+	"github.com/skatteetaten/ao/pkg/service"
+	"github.com/spf13/cobra"
+)
+
+const exampleScale = `  # Run three replicas of bar in the foo environment
+  ao scale foo/bar 3
+
+  # Pause every application in the foo environment
+  ao pause foo
+
+  # Resume bar in all environments except ref
+  ao resume bar -e ref/.*`
+
+var (
+	scaleCmd = &cobra.Command{
+		Use:         "scale <applicationDeploymentRef> <replicas>",
+		Short:       "Set replicas for one or more applications and redeploy them",
+		Example:     exampleScale,
+		Annotations: map[string]string{"type": "actions"},
+		RunE:        scale,
+	}
+
+	pauseCmd = &cobra.Command{
+		Use:         "pause <applicationDeploymentRef>",
+		Short:       "Pause one or more applications and redeploy them",
+		Example:     exampleScale,
+		Annotations: map[string]string{"type": "actions"},
+		RunE:        pause,
+	}
+
+	resumeCmd = &cobra.Command{
+		Use:         "resume <applicationDeploymentRef>",
+		Short:       "Resume one or more paused applications and redeploy them",
+		Example:     exampleScale,
+		Annotations: map[string]string{"type": "actions"},
+		RunE:        resume,
+	}
+)
+
+func init() {
+	for _, command := range []*cobra.Command{scaleCmd, pauseCmd, resumeCmd} {
+		RootCmd.AddCommand(command)
+		command.Flags().StringVarP(&flagAuroraConfig, "auroraconfig", "a", "", "Overrides the logged in AuroraConfig")
+		command.Flags().StringVarP(&flagCluster, "cluster", "c", "", "Limit to given cluster name")
+		command.Flags().BoolVarP(&flagNoPrompt, "no-prompt", "", false, "Suppress prompts")
+		command.Flags().StringArrayVarP(&flagExcludes, "exclude", "e", []string{}, "Select applications or environments to exclude")
+		command.Flags().StringVarP(&flagOverridePolicy, "override-policy", "", "", "Redeploy despite policy violations, the given reason is recorded in the journal")
+	}
+}
+
+func scale(cmd *cobra.Command, args []string) error {
+	if len(args) != 2 {
+		return cmd.Usage()
+	}
+
+	replicas, err := strconv.Atoi(args[1])
+	if err != nil || replicas < 0 {
+		return errors.Errorf("Replicas must be a non-negative number, got %s", args[1])
+	}
+
+	action := fmt.Sprintf("scale to %d replica(s)", replicas)
+	return patchAndRedeploy(cmd, args[0], action, auroraconfig.JsonPatchOp{OP: "add", Path: "/replicas", Value: replicas})
+}
+
+func pause(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return cmd.Usage()
+	}
+
+	return patchAndRedeploy(cmd, args[0], "pause", auroraconfig.JsonPatchOp{OP: "add", Path: "/pause", Value: true})
+}
+
+func resume(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return cmd.Usage()
+	}
+
+	return patchAndRedeploy(cmd, args[0], "resume", auroraconfig.JsonPatchOp{OP: "add", Path: "/pause", Value: false})
+}
+
+// patchAndRedeploy applies the operation to the application file of every matching application,
+// redeploys them and prints the resulting state when they are rolled out. The redeploy is checked against
+// the deploy policies and protected environments are confirmed before anything is changed, like for ao deploy.
+// If a patch fails, the applications already patched are still redeployed so that OpenShift matches the
+// AuroraConfig.
+func patchAndRedeploy(cmd *cobra.Command, search, action string, operation auroraconfig.JsonPatchOp) error {
+	if err := validateParams(); err != nil {
+		return err
+	}
+
+	auroraConfigName := AO.Affiliation
+	if flagAuroraConfig != "" {
+		auroraConfigName = flagAuroraConfig
+	}
+
+	apiClient, err := getAPIClient(auroraConfigName, pFlagToken, flagCluster)
+	if err != nil {
+		return err
+	}
+
+	applications, err := service.GetApplications(apiClient, search, "", flagExcludes, cmd.OutOrStdout())
+	if err != nil {
+		return err
+	} else if len(applications) == 0 {
+		return errors.New("No applications found")
+	}
+
+	specs, err := service.GetFilteredDeploymentSpecs(apiClient, applications, flagCluster)
+	if err != nil {
+		return err
+	} else if len(specs) == 0 {
+		return errors.New("No applications found")
+	}
+
+	fileNames, err := apiClient.GetFileNames()
+	if err != nil {
+		return err
+	}
+
+	policies, err := loadDeployPolicies(apiClient, policyRefClient(apiClient), fileNames)
+	if err != nil {
+		return err
+	}
+
+	policyReport, err := policies.Evaluate(policy.Deploy{
+		Specs:       specs,
+		Interactive: !flagNoPrompt,
+		Time:        time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+
+	err = enforceDeployPolicies(policyReport, flagOverridePolicy, auroraConfigName, apiClient.RefName, cmd.OutOrStdout(), cmd.ErrOrStderr())
+	if err != nil {
+		return err
+	}
+
+	header, rows := GetDeploySpecTable(specs)
+	DefaultTablePrinter(header, rows, cmd.OutOrStdout())
+	if !flagNoPrompt {
+		message := fmt.Sprintf("Do you want to %s and redeploy %d application(s)?", action, len(rows))
+		if !prompt.Confirm(message, len(rows) == 1) {
+			return errors.New("No applications were changed")
+		}
+	}
+
+	if err := confirmProtectedEnvironments(policyReport.Protected, prompt.Input); err != nil {
+		return err
+	}
+
+	patched, patchErr := patchApplicationFiles(apiClient, fileNames, specs, operation)
+	for _, fileName := range patched {
+		fmt.Fprintf(cmd.OutOrStdout(), "%s has been updated with %s %v\n", fileName, operation.Path, operation.Value)
+	}
+	if patchErr != nil {
+		if len(patched) == 0 {
+			return patchErr
+		}
+		fmt.Fprintf(cmd.ErrOrStderr(), "Warning: %s, redeploying the %d updated file(s)\n", patchErr, len(patched))
+		applications = applicationsInFiles(applications, fileNames, patched)
+	}
+
+	// Fetch the specs again so the state table compares against the updated AuroraConfig
+	specs, err = service.GetFilteredDeploymentSpecs(apiClient, applications, flagCluster)
+	if err != nil {
+		return err
+	}
+
+	partitions, err := createDeploySpecPartitions(auroraConfigName, pFlagToken, AO.Clusters, specs)
+	if err != nil {
+		return err
+	}
+
+	results, err := deployToReachableClusters(getApplicationDeploymentClient, partitions, map[string]string{})
+	if err != nil {
+		return err
+	}
+
+	recordDeployResults(auroraConfigName, apiClient.RefName, results, cmd.ErrOrStderr())
+	notifyDeployResults(apiClient, auroraConfigName, apiClient.RefName, results, cmd.ErrOrStderr())
+
+	if !deploysSucceeded(results) {
+		if err := printDeployResult(results, cmd.OutOrStdout()); err != nil {
+			return err
+		}
+		return patchErr
+	}
+
+	fmt.Fprintln(cmd.OutOrStdout(), "Waiting for the rollout to complete")
+	if err := waitForRollout(getApplicationDeploymentClient, getDeploymentStatusClient, partitions, defaultRolloutTimeout); err != nil {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Warning: %s, the rollout may still be in progress\n", err)
+	}
+
+	fmt.Fprintln(cmd.OutOrStdout(), "")
+	statuses := getApplicationStatuses(getApplicationDeploymentClient, getDeploymentStatusClient, partitions)
+	printApplicationStatuses(statuses, cmd.OutOrStdout())
+
+	return patchErr
+}
+
+// applicationsInFiles returns the applications whose application file is one of files
+func applicationsInFiles(applications []string, fileNames auroraconfig.FileNames, files []string) []string {
+	var found []string
+	for _, application := range applications {
+		fileName, err := fileNames.Find(application)
+		if err != nil {
+			continue
+		}
+		for _, file := range files {
+			if file == fileName {
+				found = append(found, application)
+				break
+			}
+		}
+	}
+	return found
+}
+
+// patchApplicationFiles patches the application file of each application once and returns the patched files
+func patchApplicationFiles(apiClient client.AuroraConfigClient, fileNames auroraconfig.FileNames, specs []deploymentspec.DeploymentSpec, operation auroraconfig.JsonPatchOp) ([]string, error) {
+	unique := make(map[string]bool)
+	for _, spec := range specs {
+		unique[spec.GetString("applicationDeploymentRef")] = true
+	}
+
+	var refs []string
+	for ref := range unique {
+		refs = append(refs, ref)
+	}
+	sort.Strings(refs)
+
+	var patched []string
+	for _, ref := range refs {
+		fileName, err := fileNames.Find(ref)
+		if err != nil {
+			return patched, err
+		}
+
+		if err := apiClient.PatchAuroraConfigFile(fileName, operation); err != nil {
+			return patched, errors.Wrapf(err, "Failed to update %s", fileName)
+		}
+		patched = append(patched, fileName)
+	}
+
+	return patched, nil
+}
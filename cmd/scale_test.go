@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/skatteetaten/ao/pkg/auroraconfig"
+	"github.com/skatteetaten/ao/pkg/client"
+	"github.com/skatteetaten/ao/pkg/deploymentspec"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_patchApplicationFiles(t *testing.T) {
+	fileNames := auroraconfig.FileNames{"about.json", "crm.json", "dev/about.json", "dev/crm.json", "dev/erp.yaml"}
+	apiClient := client.NewAuroraConfigClientMock(fileNames)
+
+	operation := auroraconfig.JsonPatchOp{OP: "add", Path: "/replicas", Value: 3}
+	apiClient.On("PatchAuroraConfigFile", "dev/crm.json", operation).Once()
+	apiClient.On("PatchAuroraConfigFile", "dev/erp.yaml", operation).Once()
+
+	specs := []deploymentspec.DeploymentSpec{
+		deploymentspec.NewDeploymentSpec("erp", "dev", "east", "1"),
+		deploymentspec.NewDeploymentSpec("crm", "dev", "east", "1"),
+		deploymentspec.NewDeploymentSpec("crm", "dev", "west", "1"),
+	}
+
+	patched, err := patchApplicationFiles(apiClient, fileNames, specs, operation)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"dev/crm.json", "dev/erp.yaml"}, patched)
+	apiClient.AssertExpectations(t)
+
+	_, err = patchApplicationFiles(apiClient, fileNames, []deploymentspec.DeploymentSpec{deploymentspec.NewDeploymentSpec("sap", "dev", "east", "1")}, operation)
+	assert.Error(t, err)
+}
+
+func Test_applicationsInFiles(t *testing.T) {
+	fileNames := auroraconfig.FileNames{"about.json", "crm.json", "dev/about.json", "dev/crm.json", "dev/erp.yaml", "test/crm.json"}
+
+	applications := applicationsInFiles([]string{"dev/crm", "dev/erp", "test/crm"}, fileNames, []string{"dev/crm.json", "test/crm.json"})
+
+	assert.Equal(t, []string{"dev/crm", "test/crm"}, applications)
+}
@@ -1,20 +1,54 @@
 package cmd
 
 import (
+	"fmt"
+	"io"
+
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 
+	"github.com/skatteetaten/ao/pkg/auroraconfig"
+	"github.com/skatteetaten/ao/pkg/prompt"
 	"github.com/skatteetaten/ao/pkg/service"
 )
 
-const setExample = `  ao set foo.json /pause true
+const setLong = `Set a configuration value in one or more files in the current AuroraConfig.
+The value is written as a boolean, number, null, object or array when it is a JSON literal of that
+type, and as a string otherwise. This applies to both JSON and YAML files.
+
+` + fileSelectorLong
+
+const fileSelectorLong = `The file is a file name, a fuzzy search or a glob pattern such as '*/crm' or 'test-*/about'.
+Unless the file is given by name, the matched files are shown and must be confirmed before they are updated.`
+
+const setExample = `  # Writes "pause": true
+  ao set foo.json /pause true
+
+  # Writes "replicas": 3
+  ao set test/foo.yaml /replicas 3
 
   ao set test/about.json /cluster utv
 
-  ao set test/foo.json /config/IMPORTANT_ENV 'Hello World'`
+  ao set test/foo.json /config/IMPORTANT_ENV 'Hello World'
+
+  # Writes "version": "1" instead of a number
+  ao set test/foo.json /version 1 --string
+
+  # Writes an object, fails if the value is not valid JSON
+  ao set test/foo.json /route --json '{"enabled": true}'
+
+  # Sets replicas for crm in all environments
+  ao set '*/crm' /replicas 2`
+
+var (
+	flagSetString bool
+	flagSetJSON   bool
+)
 
 var setCmd = &cobra.Command{
 	Use:         "set <file> <json-path> <value>",
-	Short:       "Set a single configuration value in the current AuroraConfig",
+	Short:       "Set a configuration value in one or more files in the current AuroraConfig",
+	Long:        setLong,
 	Annotations: map[string]string{"type": "remote"},
 	Example:     setExample,
 	RunE:        Set,
@@ -22,6 +56,9 @@ var setCmd = &cobra.Command{
 
 func init() {
 	RootCmd.AddCommand(setCmd)
+	setCmd.Flags().BoolVarP(&flagSetString, "string", "", false, "Always write the value as a string")
+	setCmd.Flags().BoolVarP(&flagSetJSON, "json", "", false, "Parse the value as raw JSON")
+	setCmd.Flags().BoolVarP(&flagNoPrompt, "no-prompt", "", false, "Suppress prompts")
 }
 
 func Set(cmd *cobra.Command, args []string) error {
@@ -29,14 +66,112 @@ func Set(cmd *cobra.Command, args []string) error {
 		return cmd.Usage()
 	}
 
-	name, path, value := args[0], args[1], args[2]
+	valueType, err := getSetValueType()
+	if err != nil {
+		return err
+	}
+
+	selector, path, rawValue := args[0], args[1], args[2]
 
-	fileName, err := service.SetValue(DefaultApiClient, name, path, value)
+	value, err := auroraconfig.ParseValue(rawValue, valueType)
 	if err != nil {
 		return err
 	}
 
-	cmd.Printf("%s has been updated with %s %s\n", fileName, path, value)
+	op := auroraconfig.JsonPatchOp{
+		OP:    "add",
+		Path:  path,
+		Value: value,
+	}
+
+	return patchSelectedFiles(cmd, selector, op, fmt.Sprintf(" with %s %s", path, rawValue))
+}
+
+func getSetValueType() (auroraconfig.ValueType, error) {
+	switch {
+	case flagSetString && flagSetJSON:
+		return auroraconfig.ValueInfer, errors.New("--string and --json can not be combined")
+	case flagSetString:
+		return auroraconfig.ValueString, nil
+	case flagSetJSON:
+		return auroraconfig.ValueJSON, nil
+	}
+	return auroraconfig.ValueInfer, nil
+}
+
+// patchSelectedFiles applies op to the files matching selector. A file given by name is patched directly,
+// otherwise the matched files are previewed and patched concurrently after confirmation.
+func patchSelectedFiles(cmd *cobra.Command, selector string, op auroraconfig.JsonPatchOp, detail string) error {
+	if err := op.Validate(); err != nil {
+		return err
+	}
+
+	fileNames, err := DefaultApiClient.GetFileNames()
+	if err != nil {
+		return err
+	}
+
+	if fileName, err := fileNames.Find(selector); err == nil {
+		if err := DefaultApiClient.PatchAuroraConfigFile(fileName, op); err != nil {
+			return err
+		}
+
+		cmd.Printf("%s has been updated%s\n", fileName, detail)
+		return nil
+	}
+
+	files, err := auroraconfig.SelectFiles(selector, fileNames)
+	if err != nil {
+		return err
+	} else if len(files) == 0 {
+		return errors.Errorf("No files matches %s", selector)
+	}
+
+	patch := auroraconfig.JsonPatch{op}
+	if !getPatchConfirmation(flagNoPrompt, files, patch, cmd.OutOrStdout()) {
+		return errors.New("No files updated")
+	}
+
+	results := service.PatchFiles(DefaultApiClient, files, patch)
+
+	header, rows := getPatchResultTable(results)
+	DefaultTablePrinter(header, rows, cmd.OutOrStdout())
+
+	for _, result := range results {
+		if result.Err != nil {
+			return errors.New("One or more files could not be updated")
+		}
+	}
 
 	return nil
 }
+
+func getPatchConfirmation(force bool, files []string, patch auroraconfig.JsonPatch, out io.Writer) bool {
+	var rows []string
+	for _, file := range files {
+		rows = append(rows, fmt.Sprintf("%s\t%s", file, patch))
+	}
+	DefaultTablePrinter("FILE\tCHANGE", rows, out)
+
+	if force {
+		return true
+	}
+
+	message := fmt.Sprintf("Do you want to update %d file(s)?", len(files))
+	return prompt.Confirm(message, false)
+}
+
+func getPatchResultTable(results []service.PatchResult) (string, []string) {
+	header := "\x1b[00mSTATUS\x1b[0m\tFILE\tMESSAGE"
+
+	var rows []string
+	for _, result := range results {
+		status, message := "\x1b[32mUpdated\x1b[0m", ""
+		if result.Err != nil {
+			status, message = "\x1b[31mFailed\x1b[0m", result.Err.Error()
+		}
+		rows = append(rows, fmt.Sprintf("%s\t%s\t%s", status, result.FileName, message))
+	}
+
+	return header, rows
+}
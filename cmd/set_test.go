@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/skatteetaten/ao/pkg/auroraconfig"
+	"github.com/skatteetaten/ao/pkg/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_getPatchConfirmation(t *testing.T) {
+	out := &bytes.Buffer{}
+	patch := auroraconfig.JsonPatch{{OP: "add", Path: "/replicas", Value: 2}}
+
+	assert.True(t, getPatchConfirmation(true, []string{"dev/crm.json", "test/crm.yaml"}, patch, out))
+	assert.Contains(t, out.String(), "dev/crm.json")
+	assert.Contains(t, out.String(), "test/crm.yaml")
+	assert.Contains(t, out.String(), "add /replicas 2")
+}
+
+func Test_getPatchResultTable(t *testing.T) {
+	results := []service.PatchResult{
+		{FileName: "dev/crm.json"},
+		{FileName: "test/crm.json", Err: errors.New("Resource not found")},
+	}
+
+	header, rows := getPatchResultTable(results)
+
+	assert.Equal(t, "\x1b[00mSTATUS\x1b[0m\tFILE\tMESSAGE", header)
+	assert.Equal(t, []string{
+		"\x1b[32mUpdated\x1b[0m\tdev/crm.json\t",
+		"\x1b[31mFailed\x1b[0m\ttest/crm.json\tResource not found",
+	}, rows)
+}
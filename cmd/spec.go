@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/skatteetaten/ao/pkg/deploymentspec"
+	"github.com/spf13/cobra"
+)
+
+var specCmd = &cobra.Command{
+	Use:         "spec",
+	Short:       "Save and compare deployment specs",
+	Annotations: map[string]string{"type": "remote"},
+}
+
+func init() {
+	RootCmd.AddCommand(specCmd)
+}
+
+// getSpecChangesTable lists the changed fields of every ApplicationDeploymentRef, and refs that are added or removed
+func getSpecChangesTable(changes []deploymentspec.RefChanges) (string, []string) {
+	var rows []string
+	for _, change := range changes {
+		switch {
+		case change.Added:
+			rows = append(rows, fmt.Sprintf("%s\t(added)\t\t", change.Ref))
+		case change.Removed:
+			rows = append(rows, fmt.Sprintf("%s\t(removed)\t\t", change.Ref))
+		}
+
+		ref := change.Ref
+		for _, field := range change.Changes {
+			before, after := field.Before, field.After
+			if before == "" {
+				before = "<missing>"
+			}
+			if after == "" {
+				after = "<missing>"
+			}
+			rows = append(rows, fmt.Sprintf("%s\t%s\t%s\t%s", ref, field.Path, before, after))
+			ref = " "
+		}
+	}
+
+	header := "APPLICATION\tFIELD\tBEFORE\tAFTER"
+	return header, rows
+}
@@ -0,0 +1,162 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/skatteetaten/ao/pkg/auroraconfig"
+	"github.com/skatteetaten/ao/pkg/client"
+	"github.com/skatteetaten/ao/pkg/deploymentspec"
+	"github.com/spf13/cobra"
+)
+
+const specDiffLong = `Compare the deployment specs of two applications, or of one application in two git refs.
+
+Fields that are expected to differ are ignored, use --all to compare every field:
+  /applicationDeploymentRef, /cluster, /configVersion, /envFile, /envName, /namespace
+More fields are ignored with --ignore, which also ignores the fields of an object, e.g. --ignore /route.`
+
+const exampleSpecDiff = `  # Compare crm in two environments
+  ao spec diff test-qa/crm test-st/crm
+
+  # Compare crm in master and the feature-x branch
+  ao spec diff test-qa/crm --ref master --against feature-x`
+
+// ignoredSpecFields are fields expected to differ between environments and refs
+var ignoredSpecFields = []string{"/applicationDeploymentRef", "/cluster", "/configVersion", "/envFile", "/envName", "/namespace"}
+
+var (
+	flagSpecDiffAgainst string
+	flagSpecDiffIgnore  []string
+	flagSpecDiffAll     bool
+)
+
+var specDiffCmd = &cobra.Command{
+	Use:     "diff <env/app> [env/app]",
+	Short:   "Compare the deployment specs of two applications, or of an application in two git refs",
+	Long:    specDiffLong,
+	Example: exampleSpecDiff,
+	RunE:    SpecDiff,
+}
+
+func init() {
+	specCmd.AddCommand(specDiffCmd)
+	specDiffCmd.Flags().StringVarP(&flagSpecDiffAgainst, "against", "", "", "The git ref to compare with")
+	specDiffCmd.Flags().StringArrayVarP(&flagSpecDiffIgnore, "ignore", "", []string{}, "Ignore a field, given as a path like /route")
+	specDiffCmd.Flags().BoolVarP(&flagSpecDiffAll, "all", "", false, "Compare all fields, including fields expected to differ")
+}
+
+func SpecDiff(cmd *cobra.Command, args []string) error {
+	if len(args) < 1 || len(args) > 2 {
+		return cmd.Usage()
+	}
+
+	if len(args) == 2 && flagSpecDiffAgainst != "" {
+		return errors.New("Compare either two applications, or one application in two refs with --against")
+	} else if len(args) == 1 && flagSpecDiffAgainst == "" {
+		return errors.New("Give another application to compare with, or a git ref with --against")
+	}
+
+	beforeClient, afterClient := DefaultApiClient, DefaultApiClient
+	if flagSpecDiffAgainst != "" {
+		against := *DefaultApiClient
+		against.RefName = flagSpecDiffAgainst
+		afterClient = &against
+	}
+
+	fileNames, err := beforeClient.GetFileNames()
+	if err != nil {
+		return err
+	}
+
+	beforeRef, err := findApplicationDeploymentRef(args[0], fileNames)
+	if err != nil {
+		return err
+	}
+	afterRef := beforeRef
+	if len(args) == 2 {
+		if afterRef, err = findApplicationDeploymentRef(args[1], fileNames); err != nil {
+			return err
+		}
+	}
+
+	before, err := fetchSpec(beforeClient, beforeRef)
+	if err != nil {
+		return err
+	}
+	after, err := fetchSpec(afterClient, afterRef)
+	if err != nil {
+		return err
+	}
+
+	ignored := append([]string{}, flagSpecDiffIgnore...)
+	if !flagSpecDiffAll {
+		ignored = append(ignored, ignoredSpecFields...)
+	}
+	changes := withoutIgnoredFields(deploymentspec.Diff(before, after), ignored)
+
+	beforeName := fmt.Sprintf("%s@%s", beforeRef, beforeClient.RefName)
+	afterName := fmt.Sprintf("%s@%s", afterRef, afterClient.RefName)
+	printSpecDiff(beforeName, afterName, changes, cmd.OutOrStdout())
+	return nil
+}
+
+// fetchSpec gets the deployment spec of an ApplicationDeploymentRef in the git ref of apiClient
+func fetchSpec(apiClient *client.ApiClient, ref string) (deploymentspec.DeploymentSpec, error) {
+	specs, err := apiClient.GetAuroraDeploySpec([]string{ref}, true)
+	if err != nil {
+		return nil, err
+	}
+	if len(specs) == 0 {
+		return nil, errors.Errorf("No deployment spec for %s in %s", ref, apiClient.RefName)
+	}
+	return specs[0], nil
+}
+
+// withoutIgnoredFields removes changes of the ignored fields, and of fields in ignored objects
+func withoutIgnoredFields(changes []auroraconfig.ValueChange, ignored []string) []auroraconfig.ValueChange {
+	var kept []auroraconfig.ValueChange
+	for _, change := range changes {
+		isIgnored := false
+		for _, field := range ignored {
+			field = "/" + strings.Trim(field, "/")
+			if change.Path == field || strings.HasPrefix(change.Path, field+"/") {
+				isIgnored = true
+				break
+			}
+		}
+		if !isIgnored {
+			kept = append(kept, change)
+		}
+	}
+	return kept
+}
+
+func printSpecDiff(before, after string, changes []auroraconfig.ValueChange, out io.Writer) {
+	if len(changes) == 0 {
+		fmt.Fprintf(out, "No differences between %s and %s\n", before, after)
+		return
+	}
+
+	header, rows := getSpecDiffTable(before, after, changes)
+	DefaultTablePrinter(header, rows, out)
+}
+
+func getSpecDiffTable(before, after string, changes []auroraconfig.ValueChange) (string, []string) {
+	var rows []string
+	for _, change := range changes {
+		beforeValue, afterValue := change.Before, change.After
+		if beforeValue == "" {
+			beforeValue = "<missing>"
+		}
+		if afterValue == "" {
+			afterValue = "<missing>"
+		}
+		rows = append(rows, fmt.Sprintf("%s\t%s\t%s", change.Path, beforeValue, afterValue))
+	}
+
+	header := fmt.Sprintf("FIELD\t%s\t%s", before, after)
+	return header, rows
+}
@@ -0,0 +1,160 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/skatteetaten/ao/pkg/client"
+	"github.com/skatteetaten/ao/pkg/deploymentspec"
+	"github.com/spf13/cobra"
+)
+
+const liveSnapshot = "live"
+
+const specSnapshotLong = `Save the deployment specs of all applications in the AuroraConfig, and compare them later with
+another snapshot or with the current deployment specs. This can be used to check that a refactoring
+of the AuroraConfig, e.g. moving fields to about.json, does not change any deployment spec.
+
+Snapshots are saved in the ao config folder. A name containing / or ending with .json is used as
+a path instead, e.g. to keep a snapshot as a build artifact.`
+
+const exampleSpecSnapshot = `  # Save the deployment specs of master, and compare with a feature branch after refactoring
+  ao spec snapshot save before
+  ao spec snapshot diff before live --ref refactor-about --fail-on-diff
+
+  # Compare two saved snapshots
+  ao spec snapshot diff before after`
+
+var flagFailOnDiff bool
+
+var specSnapshotCmd = &cobra.Command{
+	Use:     "snapshot",
+	Short:   "Save deployment specs and compare them later",
+	Long:    specSnapshotLong,
+	Example: exampleSpecSnapshot,
+}
+
+var specSnapshotSaveCmd = &cobra.Command{
+	Use:   "save <name>",
+	Short: "Save the deployment specs of all applications, use --ref to save another git ref",
+	RunE:  SaveSpecSnapshot,
+}
+
+var specSnapshotDiffCmd = &cobra.Command{
+	Use:   "diff <name> [other|live]",
+	Short: "Compare a snapshot with another snapshot, or with the current deployment specs",
+	RunE:  DiffSpecSnapshot,
+}
+
+func init() {
+	specCmd.AddCommand(specSnapshotCmd)
+	specSnapshotCmd.AddCommand(specSnapshotSaveCmd)
+	specSnapshotCmd.AddCommand(specSnapshotDiffCmd)
+
+	specSnapshotDiffCmd.Flags().BoolVarP(&flagFailOnDiff, "fail-on-diff", "", false, "Fail if any deployment spec differs")
+}
+
+func SaveSpecSnapshot(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return cmd.Usage()
+	}
+
+	if args[0] == liveSnapshot {
+		return errors.Errorf("%s is reserved for the current deployment specs", liveSnapshot)
+	}
+
+	snapshot, err := fetchSpecSnapshot(args[0], DefaultApiClient, time.Now())
+	if err != nil {
+		return err
+	}
+
+	path := snapshotPath(args[0])
+	if err := snapshot.Save(path); err != nil {
+		return err
+	}
+
+	cmd.Printf("Saved the deployment specs of %d application(s) in %s\n", len(snapshot.Specs), path)
+	return nil
+}
+
+func DiffSpecSnapshot(cmd *cobra.Command, args []string) error {
+	if len(args) < 1 || len(args) > 2 {
+		return cmd.Usage()
+	}
+
+	before, err := deploymentspec.LoadSnapshot(snapshotPath(args[0]))
+	if err != nil {
+		return err
+	}
+
+	var after *deploymentspec.Snapshot
+	if len(args) == 1 || args[1] == liveSnapshot {
+		after, err = fetchSpecSnapshot(liveSnapshot, DefaultApiClient, time.Now())
+	} else {
+		after, err = deploymentspec.LoadSnapshot(snapshotPath(args[1]))
+	}
+	if err != nil {
+		return err
+	}
+
+	if before.Affiliation != after.Affiliation {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Warning: Comparing snapshots of different AuroraConfigs, %s and %s\n", before.Affiliation, after.Affiliation)
+	}
+
+	changes := deploymentspec.DiffSpecs(before.Specs, after.Specs)
+	printSpecChanges(describeSnapshot(before), describeSnapshot(after), changes, cmd.OutOrStdout())
+
+	if flagFailOnDiff && len(changes) > 0 {
+		return errors.Errorf("%d application(s) have different deployment specs", len(changes))
+	}
+	return nil
+}
+
+// fetchSpecSnapshot gets the deployment specs of all ApplicationDeploymentRefs from Boober
+func fetchSpecSnapshot(name string, apiClient *client.ApiClient, now time.Time) (*deploymentspec.Snapshot, error) {
+	fileNames, err := apiClient.GetFileNames()
+	if err != nil {
+		return nil, err
+	}
+
+	var specs []deploymentspec.DeploymentSpec
+	if refs := fileNames.GetApplicationDeploymentRefs(); len(refs) > 0 {
+		specs, err = apiClient.GetAuroraDeploySpec(refs, true)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return deploymentspec.NewSnapshot(name, apiClient.Affiliation, apiClient.RefName, now, specs), nil
+}
+
+// snapshotPath returns the file of a named snapshot in the config folder, or the name if it is a path
+func snapshotPath(name string) string {
+	if strings.ContainsAny(name, `/\`) || strings.HasSuffix(name, ".json") {
+		return name
+	}
+	return filepath.Join(ConfigDir, "snapshots", DefaultApiClient.Affiliation, name+".json")
+}
+
+func describeSnapshot(snapshot *deploymentspec.Snapshot) string {
+	if snapshot.Name == liveSnapshot {
+		return fmt.Sprintf("%s (%s@%s)", snapshot.Name, snapshot.Affiliation, snapshot.RefName)
+	}
+	return fmt.Sprintf("%s (%s@%s, %s)", snapshot.Name, snapshot.Affiliation, snapshot.RefName, snapshot.Time.Local().Format("2006-01-02 15:04:05"))
+}
+
+func printSpecChanges(before, after string, changes []deploymentspec.RefChanges, out io.Writer) {
+	fmt.Fprintf(out, "Comparing %s with %s\n\n", before, after)
+	if len(changes) == 0 {
+		fmt.Fprintln(out, "No differences")
+		return
+	}
+
+	header, rows := getSpecChangesTable(changes)
+	DefaultTablePrinter(header, rows, out)
+	fmt.Fprintf(out, "\n%d application(s) differ\n", len(changes))
+}
@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/skatteetaten/ao/pkg/auroraconfig"
+	"github.com/skatteetaten/ao/pkg/client"
+	"github.com/skatteetaten/ao/pkg/deploymentspec"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_snapshotPath(t *testing.T) {
+	ConfigDir, DefaultApiClient = "/home/ao/.ao", &client.ApiClient{Affiliation: "paas"}
+
+	assert.Equal(t, filepath.Join("/home/ao/.ao", "snapshots", "paas", "before.json"), snapshotPath("before"))
+	assert.Equal(t, "build/before.json", snapshotPath("build/before.json"))
+	assert.Equal(t, "before.json", snapshotPath("before.json"))
+}
+
+func Test_printSpecChanges(t *testing.T) {
+	out := &bytes.Buffer{}
+	printSpecChanges("before", "live", nil, out)
+	assert.Equal(t, "Comparing before with live\n\nNo differences\n", out.String())
+
+	changes := []deploymentspec.RefChanges{
+		{Ref: "test/billing", Added: true},
+		{Ref: "test/crm", Changes: []auroraconfig.ValueChange{
+			{Path: "/replicas", Before: "", After: "2"},
+			{Path: "/version", Before: `"1.0.0"`, After: `"1.1.0"`},
+		}},
+		{Ref: "test/erp", Removed: true},
+	}
+
+	header, rows := getSpecChangesTable(changes)
+	assert.Equal(t, "APPLICATION\tFIELD\tBEFORE\tAFTER", header)
+	assert.Equal(t, []string{
+		"test/billing\t(added)\t\t",
+		"test/crm\t/replicas\t<missing>\t2",
+		" \t/version\t\"1.0.0\"\t\"1.1.0\"",
+		"test/erp\t(removed)\t\t",
+	}, rows)
+
+	out.Reset()
+	printSpecChanges("before", "live", changes, out)
+	assert.Contains(t, out.String(), "3 application(s) differ")
+}
+
+func Test_withoutIgnoredFields(t *testing.T) {
+	changes := []auroraconfig.ValueChange{
+		{Path: "/cluster", Before: `"utv"`, After: `"test"`},
+		{Path: "/envName", Before: `"test-qa"`, After: `"test-st"`},
+		{Path: "/replicas", Before: "2", After: "3"},
+		{Path: "/route/crm/host", Before: `"crm-qa"`, After: `"crm-st"`},
+		{Path: "/routeDefaults", Before: "", After: "true"},
+	}
+
+	kept := withoutIgnoredFields(changes, append([]string{"route"}, ignoredSpecFields...))
+	assert.Equal(t, []auroraconfig.ValueChange{
+		{Path: "/replicas", Before: "2", After: "3"},
+		{Path: "/routeDefaults", Before: "", After: "true"},
+	}, kept)
+
+	header, rows := getSpecDiffTable("test-qa/crm@master", "test-st/crm@master", kept)
+	assert.Equal(t, "FIELD\ttest-qa/crm@master\ttest-st/crm@master", header)
+	assert.Equal(t, []string{"/replicas\t2\t3", "/routeDefaults\t<missing>\ttrue"}, rows)
+
+	out := &bytes.Buffer{}
+	printSpecDiff("test-qa/crm@master", "test-st/crm@master", nil, out)
+	assert.Equal(t, "No differences between test-qa/crm@master and test-st/crm@master\n", out.String())
+}
+
+func Test_withoutIgnoredFields_environments(t *testing.T) {
+	field := func(source string, value interface{}) map[string]interface{} {
+		return map[string]interface{}{
+			"source":  source,
+			"value":   value,
+			"sources": []interface{}{map[string]interface{}{"name": source, "value": value}},
+		}
+	}
+	newSpec := func(env, cluster string) deploymentspec.DeploymentSpec {
+		return deploymentspec.DeploymentSpec{
+			"applicationDeploymentRef": field("static", env+"/crm"),
+			"cluster":                  field(env+"/about.json", cluster),
+			"configVersion":            field("default", "master"),
+			"envFile":                  field(env+"/crm.json", "about-"+cluster+".json"),
+			"envName":                  field("folderName", env),
+			"namespace":                field("generated", "sales-"+env),
+			"name":                     field("fileName", "crm"),
+			"replicas":                 field("crm.json", 2),
+		}
+	}
+
+	changes := deploymentspec.Diff(newSpec("test-qa", "utv"), newSpec("test-st", "test"))
+	assert.Len(t, changes, 5)
+	assert.Empty(t, withoutIgnoredFields(changes, ignoredSpecFields))
+}
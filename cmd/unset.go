@@ -7,11 +7,15 @@ import (
 
 const unsetExample = `  ao unset foo.json /pause
 
-  ao unset test/foo.json /config/IMPORTANT_ENV`
+  ao unset test/foo.json /config/IMPORTANT_ENV
+
+  # Removes pause from about.json in all environments starting with test-
+  ao unset 'test-*/about' /pause`
 
 var unsetCmd = &cobra.Command{
 	Use:         "unset <file> <json-path>",
-	Short:       "Remove a single configuration value in the current AuroraConfig",
+	Short:       "Remove a configuration value from one or more files in the current AuroraConfig",
+	Long:        "Remove a configuration value from one or more files in the current AuroraConfig.\n\n" + fileSelectorLong,
 	Annotations: map[string]string{"type": "remote"},
 	Example:     unsetExample,
 	RunE:        Unset,
@@ -19,6 +23,7 @@ var unsetCmd = &cobra.Command{
 
 func init() {
 	RootCmd.AddCommand(unsetCmd)
+	unsetCmd.Flags().BoolVarP(&flagNoPrompt, "no-prompt", "", false, "Suppress prompts")
 }
 
 func Unset(cmd *cobra.Command, args []string) error {
@@ -26,32 +31,10 @@ func Unset(cmd *cobra.Command, args []string) error {
 		return cmd.Usage()
 	}
 
-	fileNames, err := DefaultApiClient.GetFileNames()
-	if err != nil {
-		return err
-	}
-
-	name := args[0]
-	fileName, err := fileNames.Find(name)
-	if err != nil {
-		return err
-	}
-
-	path := args[1]
 	op := auroraconfig.JsonPatchOp{
 		OP:   "remove",
-		Path: path,
+		Path: args[1],
 	}
 
-	if err = op.Validate(); err != nil {
-		return err
-	}
-
-	if err = DefaultApiClient.PatchAuroraConfigFile(fileName, op); err != nil {
-		return err
-	}
-
-	cmd.Printf("%s has been updated\n", fileName)
-
-	return nil
+	return patchSelectedFiles(cmd, args[0], op, "")
 }
@@ -3,6 +3,7 @@ package cmd
 import (
 	"os"
 
+	"github.com/skatteetaten/ao/pkg/report"
 	"github.com/skatteetaten/ao/pkg/versioncontrol"
 	"github.com/spf13/cobra"
 )
@@ -22,10 +23,16 @@ func init() {
 	validateCmd.Flags().StringVarP(&flagAuroraConfig, "auroraconfig", "a", "", "AuroraConfig to validate")
 	validateCmd.Flags().BoolVarP(&flagFullValidation, "full", "f", false, "Validate resources")
 	validateCmd.Flags().BoolVarP(&flagRemoteValidation, "remote", "r", false, "Validate remote AuroraConfig instead of local files")
+	validateCmd.Flags().StringArrayVarP(&flagReports, "report", "", []string{}, reportUsage)
 }
 
 func Validate(cmd *cobra.Command, args []string) error {
 
+	reportTargets, err := report.ParseTargets(flagReports)
+	if err != nil {
+		return err
+	}
+
 	wd, err := os.Getwd()
 	if err != nil {
 		return err
@@ -45,14 +52,18 @@ func Validate(cmd *cobra.Command, args []string) error {
 		cmd.Printf("Validating remote AuroraConfig=%s@%s fullValidation=%t\n", DefaultApiClient.Affiliation, DefaultApiClient.RefName, flagFullValidation)
 		warnings, err = DefaultApiClient.ValidateRemoteAuroraConfig(flagFullValidation)
 	} else {
-		ac, err := versioncontrol.CollectAuroraConfigFilesInRepo(DefaultApiClient.Affiliation, gitRoot)
-		if err != nil {
-			return err
+		ac, collectErr := versioncontrol.CollectAuroraConfigFilesInRepo(DefaultApiClient.Affiliation, gitRoot)
+		if collectErr != nil {
+			return collectErr
 		}
 		cmd.Printf("Validating AuroraConfig=%s gitRoot=%s fullValidation=%t\n", DefaultApiClient.Affiliation, gitRoot, flagFullValidation)
 		warnings, err = DefaultApiClient.ValidateAuroraConfig(ac, flagFullValidation)
 	}
 
+	if reportErr := report.Write(reportTargets, newValidationReport(DefaultApiClient.Affiliation, warnings, err)); reportErr != nil {
+		return reportErr
+	}
+
 	if err != nil {
 		return err
 	}
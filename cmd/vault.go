@@ -276,11 +276,25 @@ func EditSecret(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	return editSecret(cmd, vaultName, secretName, contentToEdit, eTag, false)
+}
+
+// editSecret edits a secret file, keeping unsaved changes for recovery. The content is saved without
+// changes if unsaved is true.
+func editSecret(cmd *cobra.Command, vaultName, secretName, content, eTag string, unsaved bool) error {
+	name := vaultName + "/" + secretName
 	secretEditor := editor.NewEditor(func(modifiedContent string) error {
 		return DefaultApiClient.UpdateSecretFile(vaultName, secretName, eTag, []byte(modifiedContent))
 	})
+	secretEditor.Unsaved = unsaved
+	secretEditor.RecoveryDir = recoveryDir()
+	secretEditor.Recovery = editor.RecoveredEdit{
+		Kind:        editor.RecoveredSecretEdit,
+		Affiliation: DefaultApiClient.Affiliation,
+		Files:       []editor.RecoveredFile{{Name: name, ETag: eTag}},
+	}
 
-	err = secretEditor.Edit(contentToEdit, args[0])
+	err := secretEditor.Edit(content, name)
 	if err != nil {
 		return err
 	}
@@ -289,6 +303,16 @@ func EditSecret(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func resumeSecretEdit(cmd *cobra.Command, edit *editor.RecoveredEdit, contents map[string]string) error {
+	file := edit.Files[0]
+	split := strings.Split(file.Name, "/")
+	if len(split) != 2 {
+		return ErrNotValidSecretArgument
+	}
+
+	return editSecret(cmd, split[0], split[1], contents[file.Name], file.ETag, true)
+}
+
 func DeleteSecret(cmd *cobra.Command, args []string) error {
 	if len(args) != 1 {
 		return cmd.Usage()
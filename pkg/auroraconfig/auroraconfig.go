@@ -4,7 +4,6 @@ import (
 	"encoding/json"
 	"errors"
 	"regexp"
-	"strings"
 )
 
 type (
@@ -22,6 +21,7 @@ type (
 
 	JsonPatchOp struct {
 		OP    string      `json:"op"`
+		From  string      `json:"from,omitempty"`
 		Path  string      `json:"path"`
 		Value interface{} `json:"value"`
 	}
@@ -33,7 +33,10 @@ var (
 
 func GetApplicationRefs(filenames FileNames, pattern string, excludes []string) ([]string, error) {
 	possibleDeploys := filenames.GetApplicationDeploymentRefs()
-	applications := SearchForApplications(pattern, possibleDeploys)
+	applications := possibleDeploys
+	if pattern != "" {
+		applications = SearchForApplications(pattern, possibleDeploys)
+	}
 
 	applications, err := filterExcludes(excludes, applications)
 	if err != nil {
@@ -43,13 +46,6 @@ func GetApplicationRefs(filenames FileNames, pattern string, excludes []string)
 	return applications, nil
 }
 
-func (op JsonPatchOp) Validate() error {
-	if !strings.HasPrefix(op.Path, "/") {
-		return ErrJsonPathPrefix
-	}
-	return nil
-}
-
 func (f *AuroraConfigFile) ToPrettyJson() string {
 
 	var out map[string]interface{}
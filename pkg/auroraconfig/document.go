@@ -0,0 +1,120 @@
+package auroraconfig
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// IsYAML returns true if fileName has a YAML extension
+func IsYAML(fileName string) bool {
+	extension := strings.ToLower(filepath.Ext(fileName))
+	return extension == ".yaml" || extension == ".yml"
+}
+
+// ParseDocument parses the contents of a JSON or YAML file, chosen by the extension of fileName.
+// The returned node keeps the order of fields, and for YAML files the comments.
+func ParseDocument(fileName, contents string) (*yaml.Node, error) {
+	if !IsYAML(fileName) {
+		if err := ValidateSyntax(fileName, contents); err != nil {
+			return nil, err
+		}
+	}
+
+	var document yaml.Node
+	if err := yaml.Unmarshal([]byte(contents), &document); err != nil {
+		return nil, newYAMLSyntaxError(err)
+	}
+
+	if len(document.Content) == 0 {
+		return nil, errors.New("document is empty")
+	}
+
+	return &document, nil
+}
+
+// FormatDocument writes document as JSON or YAML, chosen by the extension of fileName.
+// JSON is indented with two spaces.
+func FormatDocument(fileName string, document *yaml.Node) (string, error) {
+	if IsYAML(fileName) {
+		buffer := &bytes.Buffer{}
+		encoder := yaml.NewEncoder(buffer)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(document); err != nil {
+			return "", err
+		}
+		return buffer.String(), nil
+	}
+
+	buffer := &bytes.Buffer{}
+	if err := writeJSON(buffer, document, ""); err != nil {
+		return "", err
+	}
+	return buffer.String() + "\n", nil
+}
+
+func writeJSON(buffer *bytes.Buffer, node *yaml.Node, indent string) error {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		return writeJSON(buffer, node.Content[0], indent)
+	case yaml.AliasNode:
+		return writeJSON(buffer, node.Alias, indent)
+	case yaml.MappingNode:
+		if len(node.Content) == 0 {
+			buffer.WriteString("{}")
+			return nil
+		}
+		buffer.WriteString("{\n")
+		for i := 0; i < len(node.Content); i += 2 {
+			fmt.Fprintf(buffer, "%s  %s: ", indent, jsonString(node.Content[i].Value))
+			if err := writeJSON(buffer, node.Content[i+1], indent+"  "); err != nil {
+				return err
+			}
+			if i+2 < len(node.Content) {
+				buffer.WriteString(",")
+			}
+			buffer.WriteString("\n")
+		}
+		buffer.WriteString(indent + "}")
+	case yaml.SequenceNode:
+		if len(node.Content) == 0 {
+			buffer.WriteString("[]")
+			return nil
+		}
+		buffer.WriteString("[\n")
+		for i, child := range node.Content {
+			buffer.WriteString(indent + "  ")
+			if err := writeJSON(buffer, child, indent+"  "); err != nil {
+				return err
+			}
+			if i+1 < len(node.Content) {
+				buffer.WriteString(",")
+			}
+			buffer.WriteString("\n")
+		}
+		buffer.WriteString(indent + "]")
+	case yaml.ScalarNode:
+		switch node.ShortTag() {
+		case "!!int", "!!float", "!!bool", "!!null":
+			buffer.WriteString(node.Value)
+		default:
+			buffer.WriteString(jsonString(node.Value))
+		}
+	default:
+		return errors.Errorf("unsupported node at line %d", node.Line)
+	}
+	return nil
+}
+
+func jsonString(value string) string {
+	buffer := &bytes.Buffer{}
+	encoder := json.NewEncoder(buffer)
+	encoder.SetEscapeHTML(false)
+	encoder.Encode(value)
+	return strings.TrimSuffix(buffer.String(), "\n")
+}
@@ -11,6 +11,13 @@ import (
 
 type FileNames []string
 
+// IsToolFile returns true for the files in the root of the AuroraConfig that configure ao itself,
+// e.g. ao-policies.json. They are not applications.
+func IsToolFile(fileName string) bool {
+	matched, _ := filepath.Match("ao-*.json", fileName)
+	return matched
+}
+
 func (f FileNames) GetApplicationDeploymentRefs() []string {
 	var filteredFiles []string
 	for _, file := range f.WithoutExtension() {
@@ -24,8 +31,9 @@ func (f FileNames) GetApplicationDeploymentRefs() []string {
 
 func (f FileNames) GetApplications() []string {
 	unique := collections.NewStringSet()
-	for _, file := range f.WithoutExtension() {
-		if !strings.ContainsRune(file, '/') && !strings.Contains(file, "about") {
+	for _, fileName := range f {
+		file := strings.TrimSuffix(fileName, filepath.Ext(fileName))
+		if !strings.ContainsRune(file, '/') && !strings.Contains(file, "about") && !IsToolFile(fileName) {
 			unique.Add(file)
 		}
 	}
@@ -0,0 +1,21 @@
+package auroraconfig
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileNames_GetApplications(t *testing.T) {
+	fileNames := FileNames{"about.json", "ao-notifications.json", "ao-policies.json", "crm.json", "erp.yaml", "dev/about.json", "dev/crm.json"}
+
+	assert.Equal(t, []string{"crm", "erp"}, fileNames.GetApplications())
+	assert.Equal(t, []string{"dev/crm"}, fileNames.GetApplicationDeploymentRefs())
+}
+
+func TestIsToolFile(t *testing.T) {
+	assert.True(t, IsToolFile("ao-policies.json"))
+	assert.True(t, IsToolFile("ao-lint.json"))
+	assert.False(t, IsToolFile("dev/ao-policies.json"))
+	assert.False(t, IsToolFile("aos.json"))
+}
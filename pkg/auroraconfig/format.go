@@ -0,0 +1,95 @@
+package auroraconfig
+
+import (
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// FormatFile formats a JSON or YAML file, chosen by the extension of fileName. Fields in the AuroraConfig
+// schema are ordered as in the schema followed by other fields in alphabetical order, and fields in free
+// form objects like config are ordered alphabetically. The order of arrays and the comments in YAML files
+// are kept.
+func FormatFile(fileName, contents string) (string, error) {
+	document, err := ParseDocument(fileName, contents)
+	if err != nil {
+		return "", err
+	}
+
+	if IsYAML(fileName) {
+		decoder := yaml.NewDecoder(strings.NewReader(contents))
+		var next yaml.Node
+		if decoder.Decode(&next) == nil && decoder.Decode(&next) != io.EOF {
+			return "", errors.New("files with more than one YAML document can not be formatted")
+		}
+	}
+
+	auroraConfigSchema.sortFields(document.Content[0])
+
+	return FormatDocument(fileName, document)
+}
+
+// sortFields sorts the fields of node and its children by the order of the schema. A nil schema sorts
+// the fields alphabetically.
+func (s *schema) sortFields(node *yaml.Node) {
+	switch node.Kind {
+	case yaml.MappingNode:
+		type field struct {
+			key, value *yaml.Node
+			rank       int
+		}
+
+		var fields []field
+		for i := 0; i < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			fields = append(fields, field{key: key, value: value, rank: s.rank(key.Value)})
+			s.property(key.Value).sortFields(value)
+		}
+
+		sort.SliceStable(fields, func(i, j int) bool {
+			if fields[i].rank != fields[j].rank {
+				return fields[i].rank < fields[j].rank
+			}
+			return fields[i].key.Value < fields[j].key.Value
+		})
+
+		for i, f := range fields {
+			node.Content[2*i], node.Content[2*i+1] = f.key, f.value
+		}
+	case yaml.SequenceNode:
+		var items *schema
+		if s != nil {
+			items = s.Items
+		}
+		for _, item := range node.Content {
+			items.sortFields(item)
+		}
+	}
+}
+
+// rank is the position of the field in the schema, fields not in the schema are ranked after all known fields
+func (s *schema) rank(field string) int {
+	if s == nil {
+		return 0
+	}
+	for i, name := range s.Order {
+		if name == field {
+			return i
+		}
+	}
+	return len(s.Order)
+}
+
+// property returns the schema of a field, or nil if it is not known
+func (s *schema) property(field string) *schema {
+	if s == nil {
+		return nil
+	}
+	if property, exists := s.Properties[field]; exists {
+		return property
+	}
+	return s.AdditionalProperties
+}
@@ -0,0 +1,59 @@
+package auroraconfig
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatFile(t *testing.T) {
+	tests := []struct {
+		FileName string
+		Contents string
+		Expected string
+	}{
+		{
+			"utv/crm.json",
+			`{"version":"1.2.3","zebra":true,"config":{"B":"b","A":1.50},"type":"deploy","route":[{"name":"crm","host":"x"}],"affiliation":"paas"}`,
+			`{
+  "type": "deploy",
+  "affiliation": "paas",
+  "version": "1.2.3",
+  "config": {
+    "A": 1.50,
+    "B": "b"
+  },
+  "route": [
+    {
+      "host": "x",
+      "name": "crm"
+    }
+  ],
+  "zebra": true
+}
+`,
+		},
+		{
+			"utv/crm.yaml",
+			"version: 1.2.3   # pinned\n# the type\ntype:    deploy\nconfig:\n   B: b\n   A: 1\n",
+			"# the type\ntype: deploy\nversion: 1.2.3 # pinned\nconfig:\n  A: 1\n  B: b\n",
+		},
+		{"about.json", "{}", "{}\n"},
+	}
+
+	for _, test := range tests {
+		formatted, err := FormatFile(test.FileName, test.Contents)
+		assert.NoError(t, err, test.FileName)
+		assert.Equal(t, test.Expected, formatted, test.FileName)
+
+		again, err := FormatFile(test.FileName, formatted)
+		assert.NoError(t, err, test.FileName)
+		assert.Equal(t, formatted, again, test.FileName)
+	}
+
+	_, err := FormatFile("crm.json", `{"type": }`)
+	assert.Error(t, err)
+
+	_, err = FormatFile("crm.yaml", "type: deploy\n---\ntype: job\n")
+	assert.EqualError(t, err, "files with more than one YAML document can not be formatted")
+}
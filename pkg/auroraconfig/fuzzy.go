@@ -1,10 +1,12 @@
 package auroraconfig
 
 import (
+	"path"
 	"path/filepath"
 	"sort"
 	"strings"
 
+	"github.com/pkg/errors"
 	"github.com/renstrom/fuzzysearch/fuzzy"
 	"github.com/skatteetaten/ao/pkg/collections"
 )
@@ -46,6 +48,35 @@ func FindMatches(search string, fileNames []string, withSuffix bool) []string {
 	return options
 }
 
+// SelectFiles returns the files matching selector. A selector containing *, ? or [ is a glob pattern
+// matched against the file names with and without extension, e.g. */crm or test-*/about.json.
+// Other selectors are matched exactly if possible, otherwise fuzzy.
+func SelectFiles(selector string, fileNames []string) ([]string, error) {
+	if !strings.ContainsAny(selector, "*?[") {
+		if fileName, err := FileNames(fileNames).Find(selector); err == nil {
+			return []string{fileName}, nil
+		}
+		return FindMatches(selector, fileNames, true), nil
+	}
+
+	if _, err := path.Match(selector, ""); err != nil {
+		return nil, errors.Wrapf(err, "Invalid file selector %s", selector)
+	}
+
+	matches := []string{}
+	for _, fileName := range fileNames {
+		withoutExtension := strings.TrimSuffix(fileName, filepath.Ext(fileName))
+		matchesName, _ := path.Match(selector, fileName)
+		matchesWithoutExtension, _ := path.Match(selector, withoutExtension)
+		if matchesName || matchesWithoutExtension {
+			matches = append(matches, fileName)
+		}
+	}
+	sort.Strings(matches)
+
+	return matches, nil
+}
+
 func SearchForFile(search string, files []string) []string {
 	return FindMatches(search, files, true)
 }
@@ -61,6 +61,31 @@ func TestFindMatches(t *testing.T) {
 	}
 }
 
+func TestSelectFiles(t *testing.T) {
+	tests := []struct {
+		Selector string
+		Expected []string
+	}{
+		{"*/boober", []string{"test-relay/boober.json", "test/boober.json", "utv-relay/boober.json", "utv/boober.json"}},
+		{"test-*/about", []string{"test-relay/about.json"}},
+		{"utv*/about.json", []string{"utv-relay/about.json", "utv/about.json"}},
+		{"*", []string{"about.json", "boober.json", "console.json"}},
+		{"?/about", []string{}},
+		{"utv/about", []string{"utv/about.json"}},
+		{"test/boober.json", []string{"test/boober.json"}},
+		{"tst/cnsl", []string{"test/console.json"}},
+	}
+
+	for _, test := range tests {
+		matches, err := SelectFiles(test.Selector, fileNames)
+		assert.NoError(t, err)
+		assert.Equal(t, test.Expected, matches, test.Selector)
+	}
+
+	_, err := SelectFiles("[utv/about", fileNames)
+	assert.Error(t, err)
+}
+
 func TestFindFileToEdit(t *testing.T) {
 	tests := []struct {
 		Search   string
@@ -0,0 +1,286 @@
+package auroraconfig
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// JsonPatch is a list of RFC 6902 operations applied to a file in one request
+type JsonPatch []JsonPatchOp
+
+var jsonPatchOperations = map[string]bool{
+	"add":     true,
+	"remove":  true,
+	"replace": true,
+	"move":    true,
+	"copy":    true,
+	"test":    true,
+}
+
+// ParseJsonPatch parses a RFC 6902 JSON patch document
+func ParseJsonPatch(data []byte) (JsonPatch, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var patch JsonPatch
+	if err := decoder.Decode(&patch); err != nil {
+		return nil, errors.Wrap(err, "JSON patch must be an array of operations")
+	}
+
+	if err := patch.Validate(); err != nil {
+		return nil, err
+	}
+
+	return patch, nil
+}
+
+func (op JsonPatchOp) Validate() error {
+	if !strings.HasPrefix(op.Path, "/") {
+		return ErrJsonPathPrefix
+	}
+	if op.OP == "move" || op.OP == "copy" {
+		if !strings.HasPrefix(op.From, "/") {
+			return errors.Errorf("%s requires from, %s", op.OP, ErrJsonPathPrefix)
+		}
+	}
+	return nil
+}
+
+// Validate validates every operation in the patch
+func (p JsonPatch) Validate() error {
+	if len(p) == 0 {
+		return errors.New("JSON patch contains no operations")
+	}
+	for i, op := range p {
+		if !jsonPatchOperations[op.OP] {
+			return errors.Errorf("operation %d: unknown op %q, must be one of add, remove, replace, move, copy or test", i+1, op.OP)
+		}
+		if err := op.Validate(); err != nil {
+			return errors.Wrapf(err, "operation %d", i+1)
+		}
+	}
+	return nil
+}
+
+// String returns one line per operation, e.g. "replace /replicas 3"
+func (p JsonPatch) String() string {
+	var lines []string
+	for _, op := range p {
+		line := fmt.Sprintf("%s %s", op.OP, op.Path)
+		switch op.OP {
+		case "move", "copy":
+			line = fmt.Sprintf("%s %s %s", op.OP, op.From, op.Path)
+		case "add", "replace", "test":
+			value, _ := json.Marshal(op.Value)
+			line = fmt.Sprintf("%s %s", line, value)
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// CreateMergePatchOps converts the RFC 7386 merge patch mergePatch into the JSON patch operations
+// that make the same change to contents. An empty patch is returned if the merge patch changes nothing.
+func CreateMergePatchOps(contents string, mergePatch []byte) (JsonPatch, error) {
+	document, err := decodeJSON(contents)
+	if err != nil {
+		return nil, errors.Wrap(err, "file is not valid JSON")
+	}
+
+	patch, err := decodeJSON(string(mergePatch))
+	if err != nil {
+		return nil, errors.Wrap(err, "merge patch is not valid JSON")
+	}
+
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("merge patch must be a JSON object")
+	}
+	documentObject, ok := document.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("file must contain a JSON object")
+	}
+
+	return mergeObjectOps("", documentObject, patchObject), nil
+}
+
+func mergeObjectOps(path string, target, patch map[string]interface{}) JsonPatch {
+	var keys []string
+	for key := range patch {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var ops JsonPatch
+	for _, key := range keys {
+		value := patch[key]
+		current, exists := target[key]
+		keyPath := path + "/" + escapePointer(key)
+
+		if value == nil {
+			if exists {
+				ops = append(ops, JsonPatchOp{OP: "remove", Path: keyPath})
+			}
+			continue
+		}
+
+		valueObject, isPatchObject := value.(map[string]interface{})
+		currentObject, isTargetObject := current.(map[string]interface{})
+		if isPatchObject && isTargetObject {
+			ops = append(ops, mergeObjectOps(keyPath, currentObject, valueObject)...)
+			continue
+		}
+
+		if isPatchObject {
+			value = withoutNulls(valueObject)
+		}
+
+		if !exists {
+			ops = append(ops, JsonPatchOp{OP: "add", Path: keyPath, Value: value})
+		} else if !reflect.DeepEqual(current, value) {
+			ops = append(ops, JsonPatchOp{OP: "replace", Path: keyPath, Value: value})
+		}
+	}
+
+	return ops
+}
+
+// withoutNulls applies a merge patch object to an empty object
+func withoutNulls(patch map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{})
+	for key, value := range patch {
+		if value == nil {
+			continue
+		}
+		if object, ok := value.(map[string]interface{}); ok {
+			value = withoutNulls(object)
+		}
+		result[key] = value
+	}
+	return result
+}
+
+func escapePointer(key string) string {
+	return strings.Replace(strings.Replace(key, "~", "~0", -1), "/", "~1", -1)
+}
+
+// ValueChange is a value that differs between two versions of a file. Before and After are
+// JSON encoded, and empty if the value does not exist.
+type ValueChange struct {
+	Path   string
+	Before string
+	After  string
+}
+
+func (c ValueChange) String() string {
+	before, after := c.Before, c.After
+	if before == "" {
+		before = "<missing>"
+	}
+	if after == "" {
+		after = "<missing>"
+	}
+	return fmt.Sprintf("%s: %s -> %s", c.Path, before, after)
+}
+
+// ChangedPaths returns the values read or written by patch that differ between original and current.
+// Both versions are parsed as JSON or YAML, chosen by the extension of fileName.
+func ChangedPaths(fileName, original, current string, patch JsonPatch) ([]ValueChange, error) {
+	originalDocument, err := decodeDocument(fileName, original)
+	if err != nil {
+		return nil, err
+	}
+	currentDocument, err := decodeDocument(fileName, current)
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	seen := make(map[string]bool)
+	for _, op := range patch {
+		for _, path := range []string{op.From, op.Path} {
+			if path != "" && !seen[path] {
+				seen[path] = true
+				paths = append(paths, path)
+			}
+		}
+	}
+
+	var changes []ValueChange
+	for _, path := range paths {
+		before, existedBefore := getPointer(originalDocument, path)
+		after, existsAfter := getPointer(currentDocument, path)
+		if existedBefore == existsAfter && reflect.DeepEqual(before, after) {
+			continue
+		}
+		changes = append(changes, ValueChange{
+			Path:   path,
+			Before: encodeValue(before, existedBefore),
+			After:  encodeValue(after, existsAfter),
+		})
+	}
+
+	return changes, nil
+}
+
+func getPointer(document interface{}, pointer string) (interface{}, bool) {
+	value := document
+	for _, token := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+		token = strings.Replace(strings.Replace(token, "~1", "/", -1), "~0", "~", -1)
+
+		switch node := value.(type) {
+		case map[string]interface{}:
+			child, exists := node[token]
+			if !exists {
+				return nil, false
+			}
+			value = child
+		case []interface{}:
+			index, err := strconv.Atoi(token)
+			if err != nil || index < 0 || index >= len(node) {
+				return nil, false
+			}
+			value = node[index]
+		default:
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// decodeDocument decodes a JSON or YAML file into the same values as decodeJSON,
+// so that values from both formats compare equal.
+func decodeDocument(fileName, contents string) (interface{}, error) {
+	if !IsYAML(fileName) {
+		return decodeJSON(contents)
+	}
+
+	document, err := ParseDocument(fileName, contents)
+	if err != nil {
+		return nil, err
+	}
+	var value interface{}
+	if err := document.Decode(&value); err != nil {
+		return nil, errors.Wrapf(err, "%s is not a valid YAML document", fileName)
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%s can not be represented as JSON", fileName)
+	}
+	return decodeJSON(string(data))
+}
+
+func encodeValue(value interface{}, exists bool) string {
+	if !exists {
+		return ""
+	}
+	data, _ := json.Marshal(value)
+	return string(data)
+}
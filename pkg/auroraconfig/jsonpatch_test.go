@@ -0,0 +1,140 @@
+package auroraconfig
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseJsonPatch(t *testing.T) {
+	patch, err := ParseJsonPatch([]byte(`[
+		{"op": "test", "path": "/version", "value": "1.2.3"},
+		{"op": "replace", "path": "/replicas", "value": 2},
+		{"op": "move", "from": "/config/A", "path": "/config/B"},
+		{"op": "copy", "from": "/route", "path": "/routeDefaults"},
+		{"op": "remove", "path": "/pause"}
+	]`))
+
+	assert.NoError(t, err)
+	assert.Len(t, patch, 5)
+	assert.Equal(t, json.Number("2"), patch[1].Value)
+	assert.Equal(t, "/config/A", patch[2].From)
+	assert.Equal(t, `test /version "1.2.3"
+replace /replicas 2
+move /config/A /config/B
+copy /route /routeDefaults
+remove /pause`, patch.String())
+
+	invalid := map[string]string{
+		`{"op": "add", "path": "/a", "value": 1}`: "JSON patch must be an array of operations",
+		`[]`:                              "JSON patch contains no operations",
+		`[{"op": "merge", "path": "/a"}]`: `operation 1: unknown op "merge"`,
+		`[{"op": "add", "path": "a"}]`:    "operation 1: json path must start with /",
+		`[{"op": "move", "path": "/a"}]`:  "operation 1: move requires from",
+	}
+
+	for data, expected := range invalid {
+		_, err := ParseJsonPatch([]byte(data))
+		if assert.Error(t, err, data) {
+			assert.Contains(t, err.Error(), expected)
+		}
+	}
+}
+
+func TestCreateMergePatchOps(t *testing.T) {
+	contents := `{
+  "version": "1.2.3",
+  "replicas": 1,
+  "pause": false,
+  "config": {"A": "a", "B": "b"},
+  "route": true,
+  "a/b": 1
+}`
+
+	patch, err := CreateMergePatchOps(contents, []byte(`{
+  "version": "1.2.3",
+  "replicas": 2,
+  "pause": null,
+  "missing": null,
+  "config": {"A": null, "C": {"D": 1, "E": null}},
+  "route": {"host": "foo", "tls": null},
+  "a/b": 2,
+  "new": [1, 2]
+}`))
+
+	assert.NoError(t, err)
+	assert.Equal(t, `replace /a~1b 2
+remove /config/A
+add /config/C {"D":1}
+add /new [1,2]
+remove /pause
+replace /replicas 2
+replace /route {"host":"foo"}`, patch.String())
+
+	patch, err = CreateMergePatchOps(contents, []byte(`{"replicas": 1, "missing": null}`))
+	assert.NoError(t, err)
+	assert.Empty(t, patch)
+
+	_, err = CreateMergePatchOps(contents, []byte(`[1]`))
+	assert.EqualError(t, err, "merge patch must be a JSON object")
+
+	_, err = CreateMergePatchOps(contents, []byte(`{"a": `))
+	assert.Error(t, err)
+
+	_, err = CreateMergePatchOps(`replicas: 1`, []byte(`{}`))
+	assert.Error(t, err)
+}
+
+func TestChangedPaths(t *testing.T) {
+	original := `{"replicas": 1, "config": {"A": "a", "B/C": "b"}, "list": [1, 2]}`
+	current := `{"replicas": 1, "config": {"A": "x", "B/C": "b", "D": "d"}, "list": [1], "pause": true}`
+
+	patch := JsonPatch{
+		{OP: "add", Path: "/replicas", Value: 2},
+		{OP: "move", From: "/config/A", Path: "/config/B~1C"},
+		{OP: "remove", Path: "/list/1"},
+		{OP: "test", Path: "/config/D"},
+	}
+
+	changes, err := ChangedPaths("about.json", original, current, patch)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []ValueChange{
+		{Path: "/config/A", Before: `"a"`, After: `"x"`},
+		{Path: "/list/1", Before: `2`},
+		{Path: "/config/D", After: `"d"`},
+	}, changes)
+	assert.Equal(t, `/list/1: 2 -> <missing>`, changes[1].String())
+
+	changes, err = ChangedPaths("about.json", original, current, JsonPatch{{OP: "add", Path: "/pause", Value: true}})
+	assert.NoError(t, err)
+	assert.Equal(t, `/pause: <missing> -> true`, changes[0].String())
+
+	changes, err = ChangedPaths("about.json", original, current, JsonPatch{{OP: "add", Path: "/version", Value: "1"}})
+	assert.NoError(t, err)
+	assert.Empty(t, changes)
+
+	_, err = ChangedPaths("about.json", "replicas: 1", current, JsonPatch{})
+	assert.Error(t, err)
+
+	originalYAML := "replicas: 1\n# scaled by hand\nconfig:\n  A: a\n"
+	currentYAML := "replicas: 1\nconfig:\n  A: x\n  B: 2.0\n"
+
+	changes, err = ChangedPaths("about.yaml", originalYAML, currentYAML, JsonPatch{{OP: "replace", Path: "/replicas", Value: 2}})
+	assert.NoError(t, err)
+	assert.Empty(t, changes)
+
+	changes, err = ChangedPaths("about.yaml", originalYAML, currentYAML, JsonPatch{
+		{OP: "replace", Path: "/config/A", Value: "b"},
+		{OP: "add", Path: "/config/B", Value: 3},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []ValueChange{
+		{Path: "/config/A", Before: `"a"`, After: `"x"`},
+		{Path: "/config/B", After: `2`},
+	}, changes)
+
+	_, err = ChangedPaths("about.yaml", originalYAML, "replicas: [", JsonPatch{})
+	assert.Error(t, err)
+}
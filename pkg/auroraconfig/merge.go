@@ -0,0 +1,219 @@
+package auroraconfig
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	conflictStart     = "<<<<<<< mine"
+	conflictSeparator = "======="
+	conflictEnd       = ">>>>>>> theirs"
+)
+
+// MergeFile does a three-way merge of the changes from base to mine and from base to theirs of a JSON or
+// YAML file. Fields changed on one side only are merged. If the same field is changed differently on both
+// sides, the paths of the fields are returned as conflicts and the merged contents mark each conflict with
+//
+//	<<<<<<< mine
+//	...
+//	=======
+//	...
+//	>>>>>>> theirs
+func MergeFile(fileName, base, mine, theirs string) (string, []string, error) {
+	var documents [3]*yaml.Node
+	for i, contents := range []string{base, mine, theirs} {
+		document, err := ParseDocument(fileName, contents)
+		if err != nil {
+			return "", nil, errors.Wrapf(err, "could not parse %s", []string{"original", "edited", "remote"}[i])
+		}
+		documents[i] = document
+	}
+
+	root := func(prefer int) (*yaml.Node, []string) {
+		merged, conflicts := mergeNodes("", documents[0].Content[0], documents[1].Content[0], documents[2].Content[0], prefer)
+		document := *documents[1]
+		document.Content = []*yaml.Node{merged}
+		return &document, conflicts
+	}
+
+	mergedMine, conflicts := root(1)
+	contentsMine, err := FormatDocument(fileName, mergedMine)
+	if err != nil || len(conflicts) == 0 {
+		return contentsMine, nil, err
+	}
+
+	mergedTheirs, _ := root(2)
+	contentsTheirs, err := FormatDocument(fileName, mergedTheirs)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return markConflicts(contentsMine, contentsTheirs), conflicts, nil
+}
+
+// HasConflictMarkers returns true if contents contains a conflict marked by MergeFile
+func HasConflictMarkers(contents string) bool {
+	for _, line := range strings.Split(contents, "\n") {
+		if line == conflictStart || line == conflictEnd {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeNodes merges a single value where nil means the value does not exist. Conflicts are resolved with
+// mine when prefer is 1 and theirs when prefer is 2.
+func mergeNodes(path string, base, mine, theirs *yaml.Node, prefer int) (*yaml.Node, []string) {
+	switch {
+	case equalNodes(mine, theirs), equalNodes(base, theirs):
+		return mine, nil
+	case equalNodes(base, mine):
+		return theirs, nil
+	}
+
+	if isMapping(mine) && isMapping(theirs) && (base == nil || isMapping(base)) {
+		return mergeMappings(path, base, mine, theirs, prefer)
+	}
+
+	if prefer == 2 {
+		return theirs, []string{path}
+	}
+	return mine, []string{path}
+}
+
+func mergeMappings(path string, base, mine, theirs *yaml.Node, prefer int) (*yaml.Node, []string) {
+	merged := *mine
+	merged.Content = nil
+
+	var keys []*yaml.Node
+	seen := make(map[string]bool)
+	for _, node := range []*yaml.Node{mine, theirs} {
+		for i := 0; i < len(node.Content); i += 2 {
+			if key := node.Content[i]; !seen[key.Value] {
+				seen[key.Value] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+
+	var conflicts []string
+	for _, key := range keys {
+		value, keyConflicts := mergeNodes(path+"/"+escapePointer(key.Value),
+			mappingValue(base, key.Value), mappingValue(mine, key.Value), mappingValue(theirs, key.Value), prefer)
+		conflicts = append(conflicts, keyConflicts...)
+		if value != nil {
+			merged.Content = append(merged.Content, key, value)
+		}
+	}
+
+	return &merged, conflicts
+}
+
+func isMapping(node *yaml.Node) bool {
+	return node != nil && node.Kind == yaml.MappingNode
+}
+
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if !isMapping(node) {
+		return nil
+	}
+	for i := 0; i < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// equalNodes compares values, ignoring style, comments and the order of fields
+func equalNodes(a, b *yaml.Node) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	if a.Kind == yaml.AliasNode {
+		return equalNodes(a.Alias, b)
+	}
+	if b.Kind == yaml.AliasNode {
+		return equalNodes(a, b.Alias)
+	}
+	if a.Kind != b.Kind || len(a.Content) != len(b.Content) {
+		return false
+	}
+
+	switch a.Kind {
+	case yaml.ScalarNode:
+		return a.ShortTag() == b.ShortTag() && a.Value == b.Value
+	case yaml.MappingNode:
+		for i := 0; i < len(a.Content); i += 2 {
+			if !equalNodes(a.Content[i+1], mappingValue(b, a.Content[i].Value)) {
+				return false
+			}
+		}
+		return true
+	default:
+		for i := range a.Content {
+			if !equalNodes(a.Content[i], b.Content[i]) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// markConflicts marks the lines that differ between mine and theirs
+func markConflicts(mine, theirs string) string {
+	a := strings.Split(mine, "\n")
+	b := strings.Split(theirs, "\n")
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var lines, mineLines, theirLines []string
+	flush := func() {
+		if len(mineLines) > 0 || len(theirLines) > 0 {
+			lines = append(lines, conflictStart)
+			lines = append(lines, mineLines...)
+			lines = append(lines, conflictSeparator)
+			lines = append(lines, theirLines...)
+			lines = append(lines, conflictEnd)
+			mineLines, theirLines = nil, nil
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			flush()
+			lines = append(lines, a[i])
+			i++
+			j++
+		case j == len(b) || (i < len(a) && lcs[i+1][j] >= lcs[i][j+1]):
+			mineLines = append(mineLines, a[i])
+			i++
+		default:
+			theirLines = append(theirLines, b[j])
+			j++
+		}
+	}
+	flush()
+
+	return strings.Join(lines, "\n")
+}
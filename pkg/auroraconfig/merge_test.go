@@ -0,0 +1,88 @@
+package auroraconfig
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const mergeBase = `{
+  "version": "1.0.0",
+  "replicas": 1,
+  "config": {
+    "A": "a",
+    "B": "b"
+  }
+}`
+
+func TestMergeFile(t *testing.T) {
+	t.Run("Should merge changes to different fields", func(t *testing.T) {
+		mine := `{"version": "1.1.0", "replicas": 1, "config": {"A": "a", "B": "b", "C": "<c>"}}`
+		theirs := `{"version": "1.0.0", "replicas": 2, "config": {"A": "a"}, "pause": true}`
+
+		merged, conflicts, err := MergeFile("foo.json", mergeBase, mine, theirs)
+
+		assert.NoError(t, err)
+		assert.Empty(t, conflicts)
+		assert.Equal(t, `{
+  "version": "1.1.0",
+  "replicas": 2,
+  "config": {
+    "A": "a",
+    "C": "<c>"
+  },
+  "pause": true
+}
+`, merged)
+	})
+
+	t.Run("Should mark conflicting changes", func(t *testing.T) {
+		mine := `{"version": "1.0.0", "replicas": 3, "config": {"A": "mine", "B": "b"}}`
+		theirs := `{"version": "1.0.0", "replicas": 2, "config": {"A": "theirs"}}`
+
+		merged, conflicts, err := MergeFile("foo.json", mergeBase, mine, theirs)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"/replicas", "/config/A"}, conflicts)
+		assert.True(t, HasConflictMarkers(merged))
+		assert.Equal(t, `{
+  "version": "1.0.0",
+<<<<<<< mine
+  "replicas": 3,
+=======
+  "replicas": 2,
+>>>>>>> theirs
+  "config": {
+<<<<<<< mine
+    "A": "mine"
+=======
+    "A": "theirs"
+>>>>>>> theirs
+  }
+}
+`, merged)
+	})
+
+	t.Run("Should merge YAML and keep comments", func(t *testing.T) {
+		base := "# Application\nversion: 1.0.0\nreplicas: 1\n"
+		mine := "# Application\nversion: 1.1.0 # pinned\nreplicas: 1\n"
+		theirs := "version: 1.0.0\nreplicas: 2\n"
+
+		merged, conflicts, err := MergeFile("foo.yaml", base, mine, theirs)
+
+		assert.NoError(t, err)
+		assert.Empty(t, conflicts)
+		assert.Equal(t, "# Application\nversion: 1.1.0 # pinned\nreplicas: 2\n", merged)
+	})
+
+	t.Run("Should fail when a version can not be parsed", func(t *testing.T) {
+		_, _, err := MergeFile("foo.json", mergeBase, `{"version": `, mergeBase)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "could not parse edited")
+	})
+}
+
+func TestHasConflictMarkers(t *testing.T) {
+	assert.False(t, HasConflictMarkers(mergeBase))
+	assert.True(t, HasConflictMarkers("{\n<<<<<<< mine\n}"))
+}
@@ -0,0 +1,233 @@
+package auroraconfig
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SchemaError is a field that does not match the AuroraConfig schema
+type SchemaError struct {
+	Line    int
+	Column  int
+	Path    string
+	Message string
+}
+
+// SchemaErrors is returned by CheckSchema when one or more fields does not match the schema
+type SchemaErrors []SchemaError
+
+type schema struct {
+	Types                []string
+	Enum                 []string
+	Properties           map[string]*schema
+	Order                []string
+	AdditionalProperties *schema
+	Closed               bool
+	Items                *schema
+}
+
+const unknownFieldMessage = "is not a known field"
+
+var auroraConfigSchema = mustParseSchema(auroraConfigSchemaJSON)
+
+func (e SchemaError) Error() string {
+	return fmt.Sprintf("line %d, column %d: %s %s", e.Line, e.Column, e.Path, e.Message)
+}
+
+// IsUnknownField returns true if the field is not in the schema, rather than having the wrong type or value
+func (e SchemaError) IsUnknownField() bool {
+	return e.Message == unknownFieldMessage
+}
+
+func (e SchemaErrors) Error() string {
+	var messages []string
+	for _, err := range e {
+		messages = append(messages, err.Error())
+	}
+	return strings.Join(messages, "\n")
+}
+
+// CheckSchema checks the fields of a JSON or YAML file against the bundled AuroraConfig schema. Unknown
+// fields and fields with the wrong type are returned as SchemaErrors. Fields in config, mounts and other
+// free form objects are not checked.
+func CheckSchema(fileName, contents string) error {
+	document, err := ParseDocument(fileName, contents)
+	if err != nil {
+		return err
+	}
+
+	violations := auroraConfigSchema.validate("", document.Content[0])
+	if len(violations) > 0 {
+		return violations
+	}
+	return nil
+}
+
+func (s *schema) validate(path string, node *yaml.Node) SchemaErrors {
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+
+	newError := func(format string, args ...interface{}) SchemaErrors {
+		fieldPath := path
+		if fieldPath == "" {
+			fieldPath = "/"
+		}
+		return SchemaErrors{{Line: node.Line, Column: node.Column, Path: fieldPath, Message: fmt.Sprintf(format, args...)}}
+	}
+
+	actual := nodeType(node)
+	if len(s.Types) > 0 && !s.allowsType(actual) {
+		return newError("must be %s, got %s", strings.Join(s.Types, " or "), actual)
+	}
+
+	if len(s.Enum) > 0 && node.Kind == yaml.ScalarNode && !s.allowsValue(node.Value) {
+		return newError("must be one of %s, got %s", strings.Join(s.Enum, ", "), node.Value)
+	}
+
+	var violations SchemaErrors
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			fieldPath := path + "/" + escapePointer(key.Value)
+
+			if property, exists := s.Properties[key.Value]; exists {
+				violations = append(violations, property.validate(fieldPath, value)...)
+			} else if s.Closed {
+				violations = append(violations, SchemaError{Line: key.Line, Column: key.Column, Path: fieldPath, Message: unknownFieldMessage})
+			} else if s.AdditionalProperties != nil {
+				violations = append(violations, s.AdditionalProperties.validate(fieldPath, value)...)
+			}
+		}
+	case yaml.SequenceNode:
+		if s.Items != nil {
+			for i, item := range node.Content {
+				violations = append(violations, s.Items.validate(fmt.Sprintf("%s/%d", path, i), item)...)
+			}
+		}
+	}
+
+	return violations
+}
+
+func (s *schema) allowsType(actual string) bool {
+	for _, expected := range s.Types {
+		if expected == actual || (expected == "number" && actual == "integer") {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *schema) allowsValue(value string) bool {
+	for _, allowed := range s.Enum {
+		if allowed == value {
+			return true
+		}
+	}
+	return false
+}
+
+func nodeType(node *yaml.Node) string {
+	switch node.Kind {
+	case yaml.MappingNode:
+		return "object"
+	case yaml.SequenceNode:
+		return "array"
+	}
+
+	switch node.ShortTag() {
+	case "!!int":
+		return "integer"
+	case "!!float":
+		return "number"
+	case "!!bool":
+		return "boolean"
+	case "!!null":
+		return "null"
+	}
+	return "string"
+}
+
+// UnmarshalJSON reads the subset of JSON schema used by the bundled schema: type, enum, properties,
+// additionalProperties and items. The order of the properties is kept in Order.
+func (s *schema) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Type                 json.RawMessage `json:"type"`
+		Enum                 []string        `json:"enum"`
+		Properties           json.RawMessage `json:"properties"`
+		AdditionalProperties json.RawMessage `json:"additionalProperties"`
+		Items                *schema         `json:"items"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	s.Enum, s.Items = raw.Enum, raw.Items
+
+	if len(raw.Properties) > 0 {
+		if err := json.Unmarshal(raw.Properties, &s.Properties); err != nil {
+			return err
+		}
+		order, err := objectKeys(raw.Properties)
+		if err != nil {
+			return err
+		}
+		s.Order = order
+	}
+
+	if len(raw.Type) > 0 {
+		if err := json.Unmarshal(raw.Type, &s.Types); err != nil {
+			var single string
+			if err := json.Unmarshal(raw.Type, &single); err != nil {
+				return err
+			}
+			s.Types = []string{single}
+		}
+	}
+
+	switch string(raw.AdditionalProperties) {
+	case "", "true":
+	case "false":
+		s.Closed = true
+	default:
+		return json.Unmarshal(raw.AdditionalProperties, &s.AdditionalProperties)
+	}
+
+	return nil
+}
+
+// objectKeys returns the keys of a JSON object in the order they are written
+func objectKeys(data []byte) ([]string, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if _, err := decoder.Token(); err != nil {
+		return nil, err
+	}
+
+	var keys []string
+	for decoder.More() {
+		key, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			return nil, err
+		}
+		keys = append(keys, key.(string))
+	}
+	return keys, nil
+}
+
+func mustParseSchema(data string) *schema {
+	var s schema
+	if err := json.Unmarshal([]byte(data), &s); err != nil {
+		panic("invalid bundled schema: " + err.Error())
+	}
+	return &s
+}
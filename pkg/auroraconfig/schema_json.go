@@ -0,0 +1,111 @@
+package auroraconfig
+
+// auroraConfigSchemaJSON describes the fields of AuroraConfig files. The same schema is used for
+// base, environment and application files since fields may be set at every level.
+const auroraConfigSchemaJSON = `{
+  "type": "object",
+  "additionalProperties": false,
+  "properties": {
+    "schemaVersion": {"type": "string"},
+    "type": {"type": "string", "enum": ["deploy", "development", "template", "localTemplate", "cronjob", "job"]},
+    "applicationPlatform": {"type": "string", "enum": ["java", "web", "python", "doozer"]},
+    "affiliation": {"type": "string"},
+    "segment": {"type": "string"},
+    "cluster": {"type": "string"},
+    "envName": {"type": "string"},
+    "name": {"type": "string"},
+    "description": {"type": "string"},
+    "message": {"type": "string"},
+    "baseFile": {"type": "string"},
+    "envFile": {"type": "string"},
+    "globalFile": {"type": "string"},
+    "includeEnvToBaseName": {"type": "boolean"},
+    "permissions": {
+      "type": "object",
+      "additionalProperties": false,
+      "properties": {
+        "admin": {"type": ["string", "array"], "items": {"type": "string"}},
+        "view": {"type": ["string", "array"], "items": {"type": "string"}},
+        "edit": {"type": ["string", "array"], "items": {"type": "string"}},
+        "adminServiceAccount": {"type": ["string", "array"], "items": {"type": "string"}}
+      }
+    },
+    "env": {
+      "type": "object",
+      "additionalProperties": false,
+      "properties": {
+        "name": {"type": "string"},
+        "ttl": {"type": "string"},
+        "autoDeploy": {"type": "boolean"}
+      }
+    },
+    "groupId": {"type": "string"},
+    "artifactId": {"type": "string"},
+    "version": {"type": ["string", "number"]},
+    "releaseTo": {"type": "string"},
+    "deployStrategy": {
+      "type": "object",
+      "additionalProperties": false,
+      "properties": {
+        "type": {"type": "string", "enum": ["rolling", "recreate"]},
+        "timeout": {"type": "integer"}
+      }
+    },
+    "replicas": {"type": "integer"},
+    "pause": {"type": "boolean"},
+    "debug": {"type": "boolean"},
+    "alarm": {"type": "boolean"},
+    "ttl": {"type": "string"},
+    "splunkIndex": {"type": "string"},
+    "serviceAccount": {"type": "string"},
+    "javaOptions": {"type": "string"},
+    "certificate": {"type": ["boolean", "string", "object"]},
+    "database": {"type": ["boolean", "string", "object"]},
+    "databaseDefaults": {"type": "object"},
+    "management": {"type": ["boolean", "string", "object"]},
+    "readiness": {"type": ["boolean", "integer", "object"]},
+    "liveness": {"type": ["boolean", "integer", "object"]},
+    "prometheus": {"type": ["boolean", "object"]},
+    "resources": {
+      "type": "object",
+      "additionalProperties": false,
+      "properties": {
+        "cpu": {
+          "type": "object",
+          "additionalProperties": false,
+          "properties": {"min": {"type": ["string", "number"]}, "max": {"type": ["string", "number"]}}
+        },
+        "memory": {
+          "type": "object",
+          "additionalProperties": false,
+          "properties": {"min": {"type": "string"}, "max": {"type": "string"}}
+        }
+      }
+    },
+    "config": {"type": "object"},
+    "secretVault": {"type": ["string", "object"]},
+    "secretVaults": {"type": "object"},
+    "route": {"type": ["boolean", "object"]},
+    "routeDefaults": {"type": "object"},
+    "mounts": {"type": "object"},
+    "webseal": {"type": ["boolean", "object"]},
+    "toxiproxy": {"type": ["boolean", "object"]},
+    "sts": {"type": ["boolean", "object"]},
+    "s3": {"type": ["boolean", "object"]},
+    "s3Defaults": {"type": "object"},
+    "bigip": {"type": "object"},
+    "nodeSelector": {"type": "object"},
+    "topology": {"type": "object"},
+    "dependencies": {"type": "object"},
+    "notification": {"type": "object"},
+    "template": {"type": "string"},
+    "templateFile": {"type": "string"},
+    "parameters": {"type": "object"},
+    "schedule": {"type": "string"},
+    "concurrent": {"type": "string", "enum": ["Allow", "Forbid", "Replace"]},
+    "startingDeadline": {"type": "integer"},
+    "suspend": {"type": "boolean"},
+    "successCount": {"type": "integer"},
+    "failureCount": {"type": "integer"}
+  }
+}`
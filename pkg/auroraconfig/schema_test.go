@@ -0,0 +1,42 @@
+package auroraconfig
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckSchema(t *testing.T) {
+	valid := `{
+  "type": "deploy",
+  "version": 1.2,
+  "replicas": 2,
+  "permissions": {"admin": ["APP_PAAS_utv"]},
+  "config": {"ANYTHING": {"goes": true}},
+  "resources": {"cpu": {"min": "100m"}},
+  "route": true
+}`
+	assert.NoError(t, CheckSchema("utv/foo.json", valid))
+
+	invalid := `{
+  "type": "deploi",
+  "replicas": "two",
+  "replica": 2,
+  "deployStrategy": {"type": "rolling", "timeOut": 60},
+  "route": "yes"
+}`
+	err := CheckSchema("utv/foo.json", invalid)
+
+	assert.IsType(t, SchemaErrors{}, err)
+	assert.Equal(t, `line 2, column 11: /type must be one of deploy, development, template, localTemplate, cronjob, job, got deploi
+line 3, column 15: /replicas must be integer, got string
+line 4, column 3: /replica is not a known field
+line 5, column 41: /deployStrategy/timeOut is not a known field
+line 6, column 12: /route must be boolean or object, got string`, err.Error())
+
+	err = CheckSchema("utv/foo.yaml", "replicas: 2\npause: yes please\n")
+	assert.EqualError(t, err, "line 2, column 8: /pause must be boolean, got string")
+
+	err = CheckSchema("utv/foo.json", `{"replicas": }`)
+	assert.IsType(t, &SyntaxError{}, err)
+}
@@ -0,0 +1,95 @@
+package auroraconfig
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SyntaxError is a JSON or YAML syntax error. Column is 0 if it is unknown.
+type SyntaxError struct {
+	Format  string
+	Line    int
+	Column  int
+	Message string
+}
+
+var yamlErrorLine = regexp.MustCompile(`^yaml: (?:unmarshal errors:\n\s*)?line (\d+): `)
+
+func (e *SyntaxError) Error() string {
+	position := fmt.Sprintf("line %d", e.Line)
+	if e.Column > 0 {
+		position += fmt.Sprintf(", column %d", e.Column)
+	}
+	return fmt.Sprintf("Invalid %s format at %s: %s", e.Format, position, e.Message)
+}
+
+// ValidateSyntax checks that contents is valid JSON or YAML, chosen by the extension of fileName.
+// A *SyntaxError is returned if the position of the error is known.
+func ValidateSyntax(fileName, contents string) error {
+	if IsYAML(fileName) {
+		var value interface{}
+		return newYAMLSyntaxError(yaml.Unmarshal([]byte(contents), &value))
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(contents))
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return newJSONSyntaxError(contents, err)
+	}
+	if decoder.More() {
+		rest := contents[decoder.InputOffset():]
+		whitespace := len(rest) - len(strings.TrimLeft(rest, " \t\r\n"))
+		line, column := position(contents, int(decoder.InputOffset())+whitespace+1)
+		return &SyntaxError{Format: "JSON", Line: line, Column: column, Message: "unexpected data after top-level value"}
+	}
+	return nil
+}
+
+func newJSONSyntaxError(contents string, err error) error {
+	if syntaxErr, ok := err.(*json.SyntaxError); ok {
+		line, column := position(contents, int(syntaxErr.Offset))
+		return &SyntaxError{Format: "JSON", Line: line, Column: column, Message: syntaxErr.Error()}
+	}
+
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		line, column := position(contents, len(contents)+1)
+		return &SyntaxError{Format: "JSON", Line: line, Column: column, Message: "unexpected end of JSON input"}
+	}
+	return err
+}
+
+func newYAMLSyntaxError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	match := yamlErrorLine.FindStringSubmatch(err.Error())
+	if match == nil {
+		return err
+	}
+
+	line, _ := strconv.Atoi(match[1])
+	message := strings.Split(strings.TrimPrefix(err.Error(), match[0]), "\n")[0]
+	return &SyntaxError{Format: "YAML", Line: line, Message: message}
+}
+
+// position returns the line and column of the byte before offset
+func position(contents string, offset int) (int, int) {
+	if offset > len(contents) {
+		offset = len(contents) + 1
+	}
+	if offset < 1 {
+		return 1, 1
+	}
+
+	before := contents[:offset-1]
+	line := strings.Count(before, "\n") + 1
+	column := len(before) - strings.LastIndex(before, "\n")
+	return line, column
+}
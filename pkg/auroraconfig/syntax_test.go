@@ -0,0 +1,36 @@
+package auroraconfig
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateSyntax(t *testing.T) {
+	tests := []struct {
+		FileName string
+		Contents string
+		Expected string
+	}{
+		{"foo.json", "{\n  \"a\": 1\n}", ""},
+		{"foo.json", "{\n  \"a\": 1\n  \"b\": 2\n}", "Invalid JSON format at line 3, column 3: invalid character '\"' after object key:value pair"},
+		{"foo.json", "{\n  \"a\": 1,\n}", "Invalid JSON format at line 3, column 1: invalid character '}' looking for beginning of object key string"},
+		{"foo.json", "{\n  \"a\": 1", "Invalid JSON format at line 2, column 9: unexpected end of JSON input"},
+		{"foo.json", "", "Invalid JSON format at line 1, column 1: unexpected end of JSON input"},
+		{"foo.json", "{}\n{}", "Invalid JSON format at line 2, column 1: unexpected data after top-level value"},
+		{"foo.yaml", "a: 1\n\tb: 2\n", "Invalid YAML format at line 2: found a tab character that violates indentation"},
+		{"foo.yaml", "a: 1\na: 2\n", `Invalid YAML format at line 2: mapping key "a" already defined at line 1`},
+		{"foo.yml", "a: 1\n  b: 2\n", "Invalid YAML format at line 2: mapping values are not allowed in this context"},
+		{"foo.yaml", "# comment\na: 1\n", ""},
+	}
+
+	for _, test := range tests {
+		err := ValidateSyntax(test.FileName, test.Contents)
+		if test.Expected == "" {
+			assert.NoError(t, err, test.Contents)
+		} else if assert.Error(t, err, test.Contents) {
+			assert.Equal(t, test.Expected, err.Error(), test.Contents)
+			assert.IsType(t, &SyntaxError{}, err)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package auroraconfig
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+// ValueType controls how a value given on the command line is converted before it is written to a file
+type ValueType int
+
+const (
+	// ValueInfer writes true/false, numbers, null, objects and arrays with their JSON type and everything else as a string
+	ValueInfer ValueType = iota
+	// ValueString always writes the value as a string
+	ValueString
+	// ValueJSON requires the value to be valid JSON
+	ValueJSON
+)
+
+// ParseValue converts raw to the value written in a JSON patch. The value is typed the same way for
+// JSON and YAML files since Boober applies the patch to both. Numbers keep their original representation,
+// so 1.10 is not written as 1.1.
+func ParseValue(raw string, valueType ValueType) (interface{}, error) {
+	if valueType == ValueString {
+		return raw, nil
+	}
+
+	value, err := decodeJSON(raw)
+	if valueType == ValueJSON {
+		if err != nil {
+			return nil, errors.Errorf("%s is not valid JSON", raw)
+		}
+		return value, nil
+	}
+
+	if _, isString := value.(string); err != nil || isString {
+		return raw, nil
+	}
+	return value, nil
+}
+
+func decodeJSON(raw string) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewBufferString(raw))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+
+	// Trailing data such as "1 2" is not a single JSON value
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, errors.New("unexpected data after value")
+	}
+
+	return value, nil
+}
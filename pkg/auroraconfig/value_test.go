@@ -0,0 +1,66 @@
+package auroraconfig
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseValue(t *testing.T) {
+	inferred := map[string]string{
+		"true":                 `true`,
+		"false":                `false`,
+		"3":                    `3`,
+		"1.10":                 `1.10`,
+		"-2e3":                 `-2e3`,
+		"null":                 `null`,
+		`{"A": 1}`:             `{"A":1}`,
+		`[1, "b"]`:             `[1,"b"]`,
+		"True":                 `"True"`,
+		"007":                  `"007"`,
+		"1.2.3":                `"1.2.3"`,
+		"Hello World":          `"Hello World"`,
+		`"quoted"`:             `"\"quoted\""`,
+		"1 2":                  `"1 2"`,
+		"":                     `""`,
+		"develop-SNAPSHOT":     `"develop-SNAPSHOT"`,
+		" 3 ":                  `3`,
+		`{"broken": `:          `"{\"broken\": "`,
+		"[utv, test]":          `"[utv, test]"`,
+		"1e400":                `1e400`,
+		"0.5":                  `0.5`,
+		"-0":                   `-0`,
+		"tRue":                 `"tRue"`,
+		"nil":                  `"nil"`,
+		"NaN":                  `"NaN"`,
+		"0x10":                 `"0x10"`,
+		"+1":                   `"+1"`,
+		"[]":                   `[]`,
+		"{}":                   `{}`,
+		`{"nested": [true]}`:   `{"nested":[true]}`,
+		"12345678901234567890": `12345678901234567890`,
+	}
+
+	for raw, expected := range inferred {
+		value, err := ParseValue(raw, ValueInfer)
+		assert.NoError(t, err, raw)
+
+		data, err := json.Marshal(value)
+		assert.NoError(t, err, raw)
+		assert.Equal(t, expected, string(data), raw)
+	}
+}
+
+func TestParseValueWithType(t *testing.T) {
+	value, err := ParseValue("true", ValueString)
+	assert.NoError(t, err)
+	assert.Equal(t, "true", value)
+
+	value, err = ParseValue(`"quoted"`, ValueJSON)
+	assert.NoError(t, err)
+	assert.Equal(t, "quoted", value)
+
+	_, err = ParseValue("Hello World", ValueJSON)
+	assert.EqualError(t, err, "Hello World is not valid JSON")
+}
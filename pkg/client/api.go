@@ -18,6 +18,9 @@ const (
 	ErrfTokenHasExpired = "Token has expired for (%s). Please login: ao login <affiliation>"
 )
 
+// ErrFileChanged is returned when a request with If-Match is rejected because the file has changed
+var ErrFileChanged = errors.New("File has changed since edit")
+
 type Doer interface {
 	Do(method string, endpoint string, payload []byte) (*BooberResponse, error)
 	DoWithHeader(method string, endpoint string, header map[string]string, payload []byte) (*ResponseBundle, error)
@@ -118,7 +121,7 @@ func (api *ApiClient) DoWithHeader(method string, endpoint string, header map[st
 	case http.StatusServiceUnavailable:
 		return nil, errors.Errorf("Service unavailable %s", api.Host)
 	case http.StatusPreconditionFailed:
-		return nil, errors.Errorf("File has changed since edit")
+		return nil, ErrFileChanged
 	}
 
 	var booberRes BooberResponse
@@ -7,6 +7,8 @@ import (
 // ApplicationDeploymentClientMock is a base mock type
 type ApplicationDeploymentClientMock struct {
 	APIClientMock
+	// ExistsResults is returned from Exists when set
+	ExistsResults []ExistsResult
 }
 
 // NewApplicationDeploymentClientMock creates a new ApplicationDeploymentClientMock
@@ -37,6 +39,10 @@ func (api *ApplicationDeploymentClientMock) Delete(deletePayload *DeletePayload)
 func (api *ApplicationDeploymentClientMock) Exists(existsPayload *ExistsPayload) (*ExistsResults, error) {
 	api.Called()
 
+	if api.ExistsResults != nil {
+		return &ExistsResults{Message: "Successful", Success: true, Results: api.ExistsResults}, nil
+	}
+
 	results := make([]ExistsResult, len(existsPayload.ApplicationDeploymentRefs))
 
 	for i, _ := range existsPayload.ApplicationDeploymentRefs {
@@ -17,6 +17,8 @@ type AuroraConfigClient interface {
 	PutAuroraConfig(endpoint string, payload []byte) (string, error)
 	ValidateAuroraConfig(ac *auroraconfig.AuroraConfig, fullValidation bool) (string, error)
 	PatchAuroraConfigFile(fileName string, operation auroraconfig.JsonPatchOp) error
+	PatchAuroraConfigFileOps(fileName string, patch auroraconfig.JsonPatch) error
+	ApplyAuroraConfigFilePatch(fileName string, patch auroraconfig.JsonPatch, eTag string) error
 	GetAuroraConfigFile(fileName string) (*auroraconfig.AuroraConfigFile, string, error)
 	PutAuroraConfigFile(file *auroraconfig.AuroraConfigFile, eTag string) error
 }
@@ -25,8 +27,24 @@ type (
 	auroraConfigFilePayload struct {
 		Content string `json:"content"`
 	}
+
+	// ConflictError is returned when values a patch depends on were changed by someone else
+	ConflictError struct {
+		FileName string
+		Changes  []auroraconfig.ValueChange
+	}
 )
 
+const maxPatchAttempts = 3
+
+func (e *ConflictError) Error() string {
+	message := fmt.Sprintf("%s was changed by someone else:", e.FileName)
+	for _, change := range e.Changes {
+		message += "\n  " + change.String()
+	}
+	return message
+}
+
 func (api *ApiClient) GetFileNames() (auroraconfig.FileNames, error) {
 	endpoint := fmt.Sprintf("/auroraconfig/%s/filenames", api.Affiliation)
 
@@ -164,21 +182,57 @@ func (api *ApiClient) GetAuroraConfigFile(fileName string) (*auroraconfig.Aurora
 	return &file, eTag, nil
 }
 
+// PatchAuroraConfigFile applies operation with the ETag of the file. If the file is changed by someone else
+// before the patch is applied, the patch is reapplied to the new contents as long as the values it touches
+// are unchanged. Otherwise a ConflictError describing the changes is returned.
 func (api *ApiClient) PatchAuroraConfigFile(fileName string, operation auroraconfig.JsonPatchOp) error {
-	endpoint := fmt.Sprintf("/auroraconfig/%s/%s", api.Affiliation, fileName)
+	return api.PatchAuroraConfigFileOps(fileName, auroraconfig.JsonPatch{operation})
+}
 
-	_, _, err := api.GetAuroraConfigFile(fileName)
+// PatchAuroraConfigFileOps applies all operations in patch in one request, and handles concurrent
+// changes like PatchAuroraConfigFile.
+func (api *ApiClient) PatchAuroraConfigFileOps(fileName string, patch auroraconfig.JsonPatch) error {
+	file, eTag, err := api.GetAuroraConfigFile(fileName)
 	if err != nil {
 		return err
 	}
 
-	op, err := json.Marshal([]auroraconfig.JsonPatchOp{operation})
+	for attempt := 1; ; attempt++ {
+		err = api.ApplyAuroraConfigFilePatch(fileName, patch, eTag)
+		if errors.Cause(err) != ErrFileChanged || attempt == maxPatchAttempts {
+			return err
+		}
+
+		current, currentETag, err := api.GetAuroraConfigFile(fileName)
+		if err != nil {
+			return err
+		}
+
+		// The patch is only reapplied when it is known not to overwrite someone else's change
+		changes, err := auroraconfig.ChangedPaths(fileName, file.Contents, current.Contents, patch)
+		if err != nil {
+			return errors.Wrapf(err, "%s was changed by someone else, and the changes could not be compared", fileName)
+		}
+		if len(changes) > 0 {
+			return &ConflictError{FileName: fileName, Changes: changes}
+		}
+
+		file, eTag = current, currentETag
+	}
+}
+
+// ApplyAuroraConfigFilePatch applies all operations in patch in one request. If eTag is given
+// the patch is rejected when the file has changed since it was fetched.
+func (api *ApiClient) ApplyAuroraConfigFilePatch(fileName string, patch auroraconfig.JsonPatch, eTag string) error {
+	endpoint := fmt.Sprintf("/auroraconfig/%s/%s", api.Affiliation, fileName)
+
+	ops, err := json.Marshal(patch)
 	if err != nil {
 		return err
 	}
 
 	payload := auroraConfigFilePayload{
-		Content: string(op),
+		Content: string(ops),
 	}
 
 	data, err := json.Marshal(payload)
@@ -186,13 +240,20 @@ func (api *ApiClient) PatchAuroraConfigFile(fileName string, operation auroracon
 		return err
 	}
 
-	response, err := api.Do(http.MethodPatch, endpoint, data)
-	if err != nil {
+	var header map[string]string
+	if eTag != "" {
+		header = map[string]string{
+			"If-Match": eTag,
+		}
+	}
+
+	bundle, err := api.DoWithHeader(http.MethodPatch, endpoint, header, data)
+	if err != nil || bundle == nil {
 		return err
 	}
 
-	if !response.Success {
-		return response.Error()
+	if !bundle.BooberResponse.Success {
+		return bundle.BooberResponse.Error()
 	}
 
 	return nil
@@ -9,6 +9,8 @@ import (
 type AuroraConfigClientMock struct {
 	APIClientMock
 	files []string
+	// Files holds the contents returned by GetAuroraConfigFile. The file name is used as ETag.
+	Files map[string]string
 }
 
 // NewAuroraConfigClientMock returns a new AurorConfigClientMock
@@ -43,15 +45,32 @@ func (api *AuroraConfigClientMock) ValidateAuroraConfig(ac *auroraconfig.AuroraC
 
 // PatchAuroraConfigFile default mock implementation
 func (api *AuroraConfigClientMock) PatchAuroraConfigFile(fileName string, operation auroraconfig.JsonPatchOp) error {
-	return errors.New("Not implemented")
+	api.Called(fileName, operation)
+	return nil
+}
+
+// PatchAuroraConfigFileOps default mock implementation
+func (api *AuroraConfigClientMock) PatchAuroraConfigFileOps(fileName string, patch auroraconfig.JsonPatch) error {
+	args := api.Called(fileName, patch)
+	return args.Error(0)
+}
+
+// ApplyAuroraConfigFilePatch default mock implementation
+func (api *AuroraConfigClientMock) ApplyAuroraConfigFilePatch(fileName string, patch auroraconfig.JsonPatch, eTag string) error {
+	api.Called(fileName, patch, eTag)
+	return nil
 }
 
 // GetAuroraConfigFile default mock implementation
 func (api *AuroraConfigClientMock) GetAuroraConfigFile(fileName string) (*auroraconfig.AuroraConfigFile, string, error) {
+	if contents, exists := api.Files[fileName]; exists {
+		return &auroraconfig.AuroraConfigFile{Name: fileName, Contents: contents}, fileName, nil
+	}
 	return nil, "", errors.New("Not implemented")
 }
 
 // PutAuroraConfigFile default mock implementation
 func (api *AuroraConfigClientMock) PutAuroraConfigFile(file *auroraconfig.AuroraConfigFile, eTag string) error {
-	return errors.New("Not implemented")
+	args := api.Called(file.Name, file.Contents, eTag)
+	return args.Error(0)
 }
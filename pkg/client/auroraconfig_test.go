@@ -194,6 +194,122 @@ func TestApiClient_PatchAuroraConfigFile(t *testing.T) {
 	})
 }
 
+func newConcurrentPatchServer(original, current string) (*httptest.Server, *int) {
+	patches := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		contents, eTag := original, "1"
+		if patches > 0 {
+			contents, eTag = current, "2"
+		}
+
+		switch req.Method {
+		case http.MethodGet:
+			w.Header().Set("ETag", eTag)
+			items, _ := json.Marshal([]auroraconfig.AuroraConfigFile{{Name: "test/foo.json", Contents: contents}})
+			data, _ := json.Marshal(BooberResponse{Success: true, Items: items, Count: 1})
+			w.Write(data)
+		case http.MethodPatch:
+			patches++
+			if req.Header.Get("If-Match") != "2" {
+				w.WriteHeader(http.StatusPreconditionFailed)
+				return
+			}
+			w.Write([]byte(`{"success": true}`))
+		}
+	}))
+	return ts, &patches
+}
+
+func TestApiClient_PatchAuroraConfigFileConflict(t *testing.T) {
+	op := auroraconfig.JsonPatchOp{OP: "add", Path: "/replicas", Value: 2}
+
+	t.Run("Should reapply patch when other values have changed", func(t *testing.T) {
+		ts, patches := newConcurrentPatchServer(`{"replicas": 1}`, `{"replicas": 1, "pause": true}`)
+		defer ts.Close()
+
+		api := NewApiClientDefaultRef(ts.URL, "", affiliation)
+		err := api.PatchAuroraConfigFile("test/foo.json", op)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, *patches)
+	})
+
+	t.Run("Should report conflict when patched value has changed", func(t *testing.T) {
+		ts, patches := newConcurrentPatchServer(`{"replicas": 1}`, `{"replicas": 3}`)
+		defer ts.Close()
+
+		api := NewApiClientDefaultRef(ts.URL, "", affiliation)
+		err := api.PatchAuroraConfigFile("test/foo.json", op)
+
+		assert.EqualError(t, err, "test/foo.json was changed by someone else:\n  /replicas: 1 -> 3")
+		assert.IsType(t, &ConflictError{}, err)
+		assert.Equal(t, 1, *patches)
+	})
+
+	t.Run("Should reapply patch to YAML file when other values have changed", func(t *testing.T) {
+		ts, patches := newConcurrentPatchServer("replicas: 1\n", "# paused\nreplicas: 1\npause: true\n")
+		defer ts.Close()
+
+		api := NewApiClientDefaultRef(ts.URL, "", affiliation)
+		err := api.PatchAuroraConfigFile("test/foo.yaml", op)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, *patches)
+	})
+
+	t.Run("Should report conflict when patched value in YAML file has changed", func(t *testing.T) {
+		ts, patches := newConcurrentPatchServer("replicas: 1\n", "replicas: 3\n")
+		defer ts.Close()
+
+		api := NewApiClientDefaultRef(ts.URL, "", affiliation)
+		err := api.PatchAuroraConfigFile("test/foo.yaml", op)
+
+		assert.EqualError(t, err, "test/foo.yaml was changed by someone else:\n  /replicas: 1 -> 3")
+		assert.Equal(t, 1, *patches)
+	})
+
+	t.Run("Should not reapply patch when changes can not be compared", func(t *testing.T) {
+		ts, patches := newConcurrentPatchServer(`{"replicas": 1}`, `replicas: 3`)
+		defer ts.Close()
+
+		api := NewApiClientDefaultRef(ts.URL, "", affiliation)
+		err := api.PatchAuroraConfigFile("test/foo.json", op)
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "test/foo.json was changed by someone else, and the changes could not be compared")
+		assert.Equal(t, 1, *patches)
+	})
+}
+
+func TestApiClient_ApplyAuroraConfigFilePatch(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, http.MethodPatch, req.Method)
+		assert.Equal(t, "abbcc", req.Header.Get("If-Match"))
+
+		var payload auroraConfigFilePayload
+		err := json.NewDecoder(req.Body).Decode(&payload)
+		assert.NoError(t, err)
+		assert.JSONEq(t, `[
+			{"op": "test", "path": "/version", "value": "1"},
+			{"op": "move", "from": "/a", "path": "/b", "value": null}
+		]`, payload.Content)
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"success": true}`))
+	}))
+	defer ts.Close()
+
+	api := NewApiClientDefaultRef(ts.URL, "", affiliation)
+
+	patch := auroraconfig.JsonPatch{
+		{OP: "test", Path: "/version", Value: "1"},
+		{OP: "move", From: "/a", Path: "/b"},
+	}
+
+	err := api.ApplyAuroraConfigFilePatch("test/foo.json", patch, "abbcc")
+	assert.NoError(t, err)
+}
+
 func TestJsonPatchOp_Validate(t *testing.T) {
 	cases := []struct {
 		JsonPath string
@@ -0,0 +1,289 @@
+package client
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// DeploymentStatusClient reads the state of running applications from OpenShift
+type DeploymentStatusClient interface {
+	GetDeploymentStatus(namespace, name string) (*DeploymentStatus, error)
+	GetApplicationDeployments(affiliation string) ([]ApplicationDeploymentResource, error)
+}
+
+// DeploymentStatus is the state of the DeploymentConfig of an application
+type DeploymentStatus struct {
+	Namespace         string
+	Name              string
+	Exists            bool
+	Version           string
+	Replicas          int
+	AvailableReplicas int
+	// RolledOut is true when the latest version is observed and all replicas are updated and available
+	RolledOut bool
+	// RolloutFailed is true when the latest rollout did not progress before its deadline
+	RolloutFailed bool
+}
+
+// ScaledDown returns true if the application runs zero replicas. This is how Boober pauses applications,
+// but an application may also be configured with zero replicas.
+func (s DeploymentStatus) ScaledDown() bool {
+	return s.Exists && s.Replicas == 0
+}
+
+// ApplicationDeploymentResource is an ApplicationDeployment on OpenShift and the ref it was deployed from
+type ApplicationDeploymentResource struct {
+	Namespace   string
+	Name        string
+	Environment string
+	Application string
+}
+
+// ApplicationDeploymentRef returns the environment/application the resource was deployed from
+func (r ApplicationDeploymentResource) ApplicationDeploymentRef() string {
+	return r.Environment + "/" + r.Application
+}
+
+// OpenShiftClient queries the OpenShift API of a cluster
+type OpenShiftClient struct {
+	Url   string
+	Token string
+}
+
+type deploymentConfig struct {
+	Metadata struct {
+		Labels     map[string]string `json:"labels"`
+		Generation int64             `json:"generation"`
+	} `json:"metadata"`
+	Spec struct {
+		Replicas int `json:"replicas"`
+		Triggers []struct {
+			Type              string `json:"type"`
+			ImageChangeParams struct {
+				From struct {
+					Name string `json:"name"`
+				} `json:"from"`
+			} `json:"imageChangeParams"`
+		} `json:"triggers"`
+		Template struct {
+			Spec struct {
+				Containers []struct {
+					Image string `json:"image"`
+				} `json:"containers"`
+			} `json:"spec"`
+		} `json:"template"`
+	} `json:"spec"`
+	Status struct {
+		ObservedGeneration int64 `json:"observedGeneration"`
+		UpdatedReplicas    int   `json:"updatedReplicas"`
+		AvailableReplicas  int   `json:"availableReplicas"`
+		Conditions         []struct {
+			Type   string `json:"type"`
+			Status string `json:"status"`
+		} `json:"conditions"`
+	} `json:"status"`
+}
+
+type resourceList struct {
+	Items []struct {
+		Metadata struct {
+			Name   string            `json:"name"`
+			Labels map[string]string `json:"labels"`
+		} `json:"metadata"`
+		Spec struct {
+			Command struct {
+				ApplicationDeploymentRef applicationDeploymentRef `json:"applicationDeploymentRef"`
+			} `json:"command"`
+		} `json:"spec"`
+	} `json:"items"`
+}
+
+var openShiftHTTPClient = &http.Client{
+	Timeout:   10 * time.Second,
+	Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}},
+}
+
+// NewOpenShiftClient creates a client for the OpenShift API at url
+func NewOpenShiftClient(url, token string) *OpenShiftClient {
+	return &OpenShiftClient{Url: url, Token: token}
+}
+
+// GetDeploymentStatus returns the status of the DeploymentConfig name in namespace. A missing
+// DeploymentConfig is not an error, it is reported with Exists false.
+func (c *OpenShiftClient) GetDeploymentStatus(namespace, name string) (*DeploymentStatus, error) {
+	status := &DeploymentStatus{Namespace: namespace, Name: name}
+
+	var dc deploymentConfig
+	path := fmt.Sprintf("/apis/apps.openshift.io/v1/namespaces/%s/deploymentconfigs/%s", namespace, name)
+	found, err := c.get(path, &dc)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to read %s/%s", namespace, name)
+	} else if !found {
+		return status, nil
+	}
+
+	status.Exists = true
+	status.Replicas = dc.Spec.Replicas
+	status.AvailableReplicas = dc.Status.AvailableReplicas
+	status.Version = dc.version()
+	status.RolledOut = dc.rolledOut()
+	status.RolloutFailed = dc.rolloutFailed()
+
+	return status, nil
+}
+
+// rolledOut returns true when OpenShift has observed the latest change, and every replica runs it
+func (dc deploymentConfig) rolledOut() bool {
+	return dc.Status.ObservedGeneration >= dc.Metadata.Generation &&
+		dc.Status.UpdatedReplicas == dc.Spec.Replicas &&
+		dc.Status.AvailableReplicas >= dc.Spec.Replicas
+}
+
+// rolloutFailed returns true when the Progressing condition is false, which OpenShift sets when a
+// rollout has failed or exceeded its deadline
+func (dc deploymentConfig) rolloutFailed() bool {
+	for _, condition := range dc.Status.Conditions {
+		if condition.Type == "Progressing" && condition.Status == "False" {
+			return true
+		}
+	}
+	return false
+}
+
+// GetApplicationDeployments lists the ApplicationDeployments in every namespace of the affiliation the user can see
+func (c *OpenShiftClient) GetApplicationDeployments(affiliation string) ([]ApplicationDeploymentResource, error) {
+	var projects resourceList
+	if _, err := c.get("/apis/project.openshift.io/v1/projects", &projects); err != nil {
+		return nil, errors.Wrap(err, "Failed to list projects")
+	}
+
+	affiliations := make(map[string]bool)
+	for _, project := range projects.Items {
+		if label := project.Metadata.Labels["affiliation"]; label != "" {
+			affiliations[label] = true
+		}
+	}
+
+	var resources []ApplicationDeploymentResource
+	for _, project := range projects.Items {
+		namespace := project.Metadata.Name
+		if !inAffiliation(namespace, project.Metadata.Labels["affiliation"], affiliation, affiliations) {
+			continue
+		}
+
+		var applicationDeployments resourceList
+		path := fmt.Sprintf("/apis/skatteetaten.no/v1/namespaces/%s/applicationdeployments", namespace)
+		if _, err := c.get(path, &applicationDeployments); err != nil {
+			return nil, errors.Wrapf(err, "Failed to list ApplicationDeployments in %s", namespace)
+		}
+
+		for _, item := range applicationDeployments.Items {
+			resource := ApplicationDeploymentResource{
+				Namespace:   namespace,
+				Name:        item.Metadata.Name,
+				Environment: item.Spec.Command.ApplicationDeploymentRef.Environment,
+				Application: item.Spec.Command.ApplicationDeploymentRef.Application,
+			}
+			if resource.Environment == "" || resource.Application == "" {
+				resource.Environment = strings.TrimPrefix(namespace, affiliation+"-")
+				resource.Application = item.Metadata.Name
+			}
+			resources = append(resources, resource)
+		}
+	}
+
+	return resources, nil
+}
+
+// inAffiliation returns true if the project namespace with the affiliation label belongs to affiliation.
+// Projects without the label are matched on the namespace, unless the namespace also starts with a longer
+// affiliation in affiliations, e.g. sales-eu-dev is not in sales when there is an affiliation sales-eu.
+func inAffiliation(namespace, label, affiliation string, affiliations map[string]bool) bool {
+	if label != "" {
+		return label == affiliation
+	}
+
+	if !strings.HasPrefix(namespace, affiliation+"-") {
+		return false
+	}
+	for other := range affiliations {
+		if len(other) > len(affiliation) && strings.HasPrefix(namespace, other+"-") {
+			return false
+		}
+	}
+	return true
+}
+
+// get reads the resource at path into v. A missing resource is reported as not found rather than an error.
+func (c *OpenShiftClient) get(path string, v interface{}) (bool, error) {
+	url := strings.TrimSuffix(c.Url, "/") + path
+	logrus.WithField("url", url).Info("Request")
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.Token)
+
+	res, err := openShiftHTTPClient.Do(req)
+	if err != nil {
+		return false, errors.Wrap(err, "Error connecting to OpenShift")
+	}
+	defer res.Body.Close()
+
+	switch {
+	case res.StatusCode == http.StatusNotFound:
+		return false, nil
+	case res.StatusCode == http.StatusUnauthorized || res.StatusCode == http.StatusForbidden:
+		return false, errors.New("Not authorized, try ao login")
+	case res.StatusCode > 299:
+		return false, errors.Errorf("OpenShift responded with %s", res.Status)
+	}
+
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return false, err
+	}
+
+	if err := json.Unmarshal(body, v); err != nil {
+		return false, errors.Wrap(err, "Invalid response from OpenShift")
+	}
+
+	return true, nil
+}
+
+// version returns the version label set by Boober, or the tag of the container image. The ImageChange
+// trigger is not used, since Boober triggers on the default tag of the ImageStream.
+func (dc deploymentConfig) version() string {
+	if version := dc.Metadata.Labels["version"]; version != "" {
+		return version
+	}
+
+	for _, container := range dc.Spec.Template.Spec.Containers {
+		if tag := imageTag(container.Image); tag != "" {
+			return tag
+		}
+	}
+
+	return "-"
+}
+
+func imageTag(image string) string {
+	if index := strings.Index(image, "@"); index >= 0 {
+		image = image[:index]
+	}
+	index := strings.LastIndex(image, ":")
+	if index < 0 || strings.Contains(image[index:], "/") {
+		return ""
+	}
+	return image[index+1:]
+}
@@ -0,0 +1,38 @@
+package client
+
+import (
+	"github.com/stretchr/testify/mock"
+)
+
+// DeploymentStatusClientMock is a base mock type
+type DeploymentStatusClientMock struct {
+	mock.Mock
+	statuses map[string]DeploymentStatus
+	// ApplicationDeployments is returned from GetApplicationDeployments
+	ApplicationDeployments []ApplicationDeploymentResource
+}
+
+// NewDeploymentStatusClientMock creates a new DeploymentStatusClientMock returning the given statuses
+func NewDeploymentStatusClientMock(statuses ...DeploymentStatus) *DeploymentStatusClientMock {
+	mock := &DeploymentStatusClientMock{statuses: make(map[string]DeploymentStatus)}
+	for _, status := range statuses {
+		mock.statuses[status.Namespace+"/"+status.Name] = status
+	}
+	return mock
+}
+
+// GetDeploymentStatus default mock implementation, unknown applications does not exist
+func (api *DeploymentStatusClientMock) GetDeploymentStatus(namespace, name string) (*DeploymentStatus, error) {
+	api.Called(namespace, name)
+
+	if status, ok := api.statuses[namespace+"/"+name]; ok {
+		return &status, nil
+	}
+	return &DeploymentStatus{Namespace: namespace, Name: name}, nil
+}
+
+// GetApplicationDeployments default mock implementation
+func (api *DeploymentStatusClientMock) GetApplicationDeployments(affiliation string) ([]ApplicationDeploymentResource, error) {
+	api.Called(affiliation)
+	return api.ApplicationDeployments, nil
+}
@@ -0,0 +1,113 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenShiftClient_GetDeploymentStatus(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "Bearer token", req.Header.Get("Authorization"))
+
+		switch req.URL.Path {
+		case "/apis/apps.openshift.io/v1/namespaces/sales-dev/deploymentconfigs/crm":
+			w.Write([]byte(`{
+				"metadata": {"labels": {"app": "crm", "version": "1.2.3"}},
+				"spec": {
+					"replicas": 2,
+					"triggers": [{"type": "ConfigChange"}, {"type": "ImageChange", "imageChangeParams": {"from": {"name": "crm:default"}}}],
+					"template": {"spec": {"containers": [{"image": "docker-registry:5000/sales/crm@sha256:abc"}]}}
+				},
+				"status": {"observedGeneration": 4, "updatedReplicas": 2, "availableReplicas": 1}
+			}`))
+		case "/apis/apps.openshift.io/v1/namespaces/sales-dev/deploymentconfigs/erp":
+			w.Write([]byte(`{"spec": {"replicas": 0, "template": {"spec": {"containers": [{"image": "docker-registry:5000/sales/erp:2"}]}}}}`))
+		case "/apis/apps.openshift.io/v1/namespaces/sales-dev/deploymentconfigs/hr":
+			w.Write([]byte(`{
+				"metadata": {"generation": 3},
+				"spec": {"replicas": 1},
+				"status": {"observedGeneration": 3, "updatedReplicas": 0, "availableReplicas": 1, "conditions": [{"type": "Progressing", "status": "False"}]}
+			}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	openShift := NewOpenShiftClient(ts.URL, "token")
+
+	status, err := openShift.GetDeploymentStatus("sales-dev", "crm")
+	assert.NoError(t, err)
+	assert.Equal(t, DeploymentStatus{Namespace: "sales-dev", Name: "crm", Exists: true, Version: "1.2.3", Replicas: 2, AvailableReplicas: 1}, *status)
+	assert.False(t, status.ScaledDown())
+
+	status, err = openShift.GetDeploymentStatus("sales-dev", "erp")
+	assert.NoError(t, err)
+	assert.Equal(t, "2", status.Version)
+	assert.True(t, status.ScaledDown())
+	assert.True(t, status.RolledOut)
+
+	status, err = openShift.GetDeploymentStatus("sales-dev", "hr")
+	assert.NoError(t, err)
+	assert.False(t, status.RolledOut)
+	assert.True(t, status.RolloutFailed)
+
+	status, err = openShift.GetDeploymentStatus("sales-dev", "sap")
+	assert.NoError(t, err)
+	assert.False(t, status.Exists)
+	assert.False(t, status.ScaledDown())
+}
+
+func Test_imageTag(t *testing.T) {
+	assert.Equal(t, "1.2.3", imageTag("crm:1.2.3"))
+	assert.Equal(t, "latest", imageTag("registry:5000/sales/crm:latest@sha256:abc"))
+	assert.Equal(t, "", imageTag("registry:5000/sales/crm"))
+}
+
+func TestOpenShiftClient_GetApplicationDeployments(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/apis/project.openshift.io/v1/projects":
+			w.Write([]byte(`{"items": [
+				{"metadata": {"name": "sales-dev", "labels": {"affiliation": "sales"}}},
+				{"metadata": {"name": "sales-test"}},
+				{"metadata": {"name": "paas-dev", "labels": {"affiliation": "paas"}}},
+				{"metadata": {"name": "sales-eu-dev", "labels": {"affiliation": "sales-eu"}}},
+				{"metadata": {"name": "sales-eu-test"}},
+				{"metadata": {"name": "sales-prod", "labels": {"affiliation": "paas"}}}
+			]}`))
+		case "/apis/skatteetaten.no/v1/namespaces/sales-dev/applicationdeployments":
+			w.Write([]byte(`{"items": [{"metadata": {"name": "crm"}, "spec": {"command": {"applicationDeploymentRef": {"environment": "dev", "application": "crm"}}}}]}`))
+		case "/apis/skatteetaten.no/v1/namespaces/sales-test/applicationdeployments":
+			w.Write([]byte(`{"items": [{"metadata": {"name": "erp"}}]}`))
+		default:
+			t.Errorf("Unexpected request to %s", req.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	resources, err := NewOpenShiftClient(ts.URL, "token").GetApplicationDeployments("sales")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []ApplicationDeploymentResource{
+		{Namespace: "sales-dev", Name: "crm", Environment: "dev", Application: "crm"},
+		{Namespace: "sales-test", Name: "erp", Environment: "test", Application: "erp"},
+	}, resources)
+	assert.Equal(t, "test/erp", resources[1].ApplicationDeploymentRef())
+}
+
+func Test_inAffiliation(t *testing.T) {
+	affiliations := map[string]bool{"sales": true, "sales-eu": true}
+
+	assert.True(t, inAffiliation("sales-dev", "sales", "sales", affiliations))
+	assert.True(t, inAffiliation("crm", "sales", "sales", affiliations))
+	assert.False(t, inAffiliation("sales-dev", "paas", "sales", affiliations))
+	assert.True(t, inAffiliation("sales-test", "", "sales", affiliations))
+	assert.False(t, inAffiliation("sales-eu-test", "", "sales", affiliations))
+	assert.True(t, inAffiliation("sales-eu-test", "", "sales-eu", affiliations))
+	assert.False(t, inAffiliation("paas-test", "", "sales", affiliations))
+}
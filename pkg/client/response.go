@@ -22,6 +22,19 @@ type (
 		IllegalFieldErrors []string
 		MissingFieldErrors []string
 		InvalidFieldErrors []string
+		Details            []ErrorDetail
+	}
+
+	// ErrorDetail is a single validation error for an application
+	ErrorDetail struct {
+		Environment string
+		Application string
+		Type        string
+		FileName    string
+		Path        string
+		Message     string
+		// Formatted is the error as presented in the String of the ErrorResponse
+		Formatted string
 	}
 
 	errorField struct {
@@ -76,11 +89,16 @@ func (res *BooberResponse) Error() error {
 		return err
 	}
 	if errRes != nil {
-		return errors.New(errRes.String())
+		return errRes
 	}
 	return nil
 }
 
+// Error makes the ErrorResponse usable as an error, callers can inspect Details with a type assertion
+func (e *ErrorResponse) Error() string {
+	return e.String()
+}
+
 func (e *ErrorResponse) String() string {
 	var status string
 
@@ -181,6 +199,7 @@ Message:     %s`
 					message.Message,
 				)
 				e.IllegalFieldErrors = append(e.IllegalFieldErrors, illegal)
+				e.addDetail(res, message.Type, message.Field, message.Message, illegal)
 			}
 
 		case "INVALID":
@@ -193,6 +212,7 @@ Message:     %s`
 					message.Message,
 				)
 				e.InvalidFieldErrors = append(e.InvalidFieldErrors, invalid)
+				e.addDetail(res, message.Type, message.Field, message.Message, invalid)
 			}
 
 		case "MISSING":
@@ -204,6 +224,7 @@ Message:     %s`
 					message.Message,
 				)
 				e.MissingFieldErrors = append(e.MissingFieldErrors, missing)
+				e.addDetail(res, message.Type, message.Field, message.Message, missing)
 			}
 		case "GENERIC":
 			{
@@ -213,8 +234,21 @@ Message:     %s`
 					message.Message,
 				)
 				e.GenericErrors = append(e.GenericErrors, generic)
+				e.addDetail(res, message.Type, message.Field, message.Message, generic)
 
 			}
 		}
 	}
 }
+
+func (e *ErrorResponse) addDetail(res *responseErrorItem, errorType string, field errorField, message, formatted string) {
+	e.Details = append(e.Details, ErrorDetail{
+		Environment: res.Environment,
+		Application: res.Application,
+		Type:        errorType,
+		FileName:    field.FileName,
+		Path:        field.Path,
+		Message:     message,
+		Formatted:   formatted,
+	})
+}
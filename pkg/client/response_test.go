@@ -149,4 +149,16 @@ func TestResponse_ToErrorResponse(t *testing.T) {
 	assert.Len(t, errorResponse.MissingFieldErrors, 1)
 
 	assert.Len(t, errorResponse.getAllErrors(), 4)
+
+	assert.Len(t, errorResponse.Details, 4)
+	for _, detail := range errorResponse.Details {
+		assert.NotEmpty(t, detail.Application)
+		assert.NotEmpty(t, detail.Message)
+		assert.Contains(t, errorResponse.getAllErrors(), detail.Formatted)
+	}
+
+	err = response.Error()
+	_, isErrorResponse := err.(*ErrorResponse)
+	assert.True(t, isErrorResponse)
+	assert.Equal(t, errorResponse.String(), err.Error())
 }
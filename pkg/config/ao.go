@@ -11,6 +11,8 @@ import (
 
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
+	"github.com/skatteetaten/ao/pkg/notify"
+	"github.com/skatteetaten/ao/pkg/policy"
 	"github.com/skatteetaten/ao/pkg/prompt"
 )
 
@@ -27,6 +29,10 @@ type AOConfig struct {
 	ClusterUrlPattern       string   `json:"clusterUrlPattern"`
 	BooberUrlPattern        string   `json:"booberUrlPattern"`
 	UpdateUrlPattern        string   `json:"updateUrlPattern"`
+
+	DeployStageOrder []string        `json:"deployStageOrder,omitempty"`
+	DeployPolicies   policy.Policies `json:"deployPolicies,omitempty"`
+	Notifications    notify.Webhooks `json:"notifications,omitempty"`
 }
 
 var DefaultAOConfig = AOConfig{
@@ -2,12 +2,19 @@ package deploymentspec
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 )
 
 // DeploymentSpec represented as an empty interface.
 type DeploymentSpec map[string]interface{}
 
+// FieldSource is a file or other source that sets the value of a field
+type FieldSource struct {
+	Name  string
+	Value interface{}
+}
+
 // Get returns value of specified field.
 func (spec DeploymentSpec) Get(jsonPointer string) interface{} {
 	return spec.get(jsonPointer+"/value", "-")
@@ -28,6 +35,53 @@ func (spec DeploymentSpec) HasValue(name string) bool {
 	return !strings.EqualFold(spec.GetString(name), "-")
 }
 
+// Fields returns the JSON pointers of all fields in the deployment spec, sorted alphabetically
+func (spec DeploymentSpec) Fields() []string {
+	var pointers []string
+	var walk func(prefix string, node map[string]interface{})
+	walk = func(prefix string, node map[string]interface{}) {
+		for key, value := range node {
+			child, ok := value.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if _, isField := child["sources"].([]interface{}); isField {
+				pointers = append(pointers, prefix+"/"+key)
+			} else {
+				walk(prefix+"/"+key, child)
+			}
+		}
+	}
+	walk("", spec)
+
+	sort.Strings(pointers)
+	return pointers
+}
+
+// Source returns the name of the file or other source that set the value of the field
+func (spec DeploymentSpec) Source(jsonPointer string) string {
+	return fmt.Sprintf("%v", spec.get(jsonPointer+"/source", "-"))
+}
+
+// Sources returns every source that sets the field, with the source that set the value last.
+// Earlier sources are overridden.
+func (spec DeploymentSpec) Sources(jsonPointer string) []FieldSource {
+	raw, ok := spec.get(jsonPointer+"/sources", "-").([]interface{})
+	if !ok {
+		return nil
+	}
+
+	var sources []FieldSource
+	for _, item := range raw {
+		source, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		sources = append(sources, FieldSource{Name: fmt.Sprintf("%v", source["name"]), Value: source["value"]})
+	}
+	return sources
+}
+
 // Cluster returns value of the cluster field.
 func (spec DeploymentSpec) Cluster() string {
 	return spec.GetString("cluster")
@@ -65,13 +119,18 @@ func (spec DeploymentSpec) get(jsonPointer, defaultValue string) interface{} {
 	current := spec
 	for i, pointer := range pointers {
 		isLast := i == len(pointers)-1
-		if next, ok := current[pointer]; ok && isLast {
+		next, ok := current[pointer]
+		if !ok {
+			return defaultValue
+		} else if isLast {
 			return next
-		} else if ok && !isLast {
-			current = next.(map[string]interface{})
-		} else {
+		}
+
+		nextMap, isMap := next.(map[string]interface{})
+		if !isMap {
 			return defaultValue
 		}
+		current = nextMap
 	}
 	return defaultValue
 }
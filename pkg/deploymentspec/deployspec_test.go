@@ -78,3 +78,33 @@ func Test_NewDeploymentSpec(t *testing.T) {
 	assert.Equal(t, "1", deploySpec.Version())
 	assert.Equal(t, "-", deploySpec.GetString("/does/not/exist"))
 }
+
+func Test_GetStringThroughValue(t *testing.T) {
+	deploySpec := readTestFile(t)
+	assert.Equal(t, "-", deploySpec.GetString("/version/value/major"))
+}
+
+func Test_Source(t *testing.T) {
+	deploySpec := readTestFile(t)
+	assert.Equal(t, "dev/about.json", deploySpec.Source("cluster"))
+	assert.Equal(t, "flubber.json", deploySpec.Source("/resources/cpu/max"))
+	assert.Equal(t, "-", deploySpec.Source("/does/not/exist"))
+}
+
+func Test_Sources(t *testing.T) {
+	deploySpec := readTestFile(t)
+	assert.Equal(t, []FieldSource{
+		{Name: "fileName", Value: "flubber"},
+		{Name: "flubber.json", Value: "flubber"},
+		{Name: "dev/flubber.yaml", Value: "flubber"},
+	}, deploySpec.Sources("/name"))
+	assert.Empty(t, deploySpec.Sources("/resources"))
+	assert.Empty(t, deploySpec.Sources("/does/not/exist"))
+}
+
+func Test_Fields(t *testing.T) {
+	fields := readTestFile(t).Fields()
+	assert.Contains(t, fields, "/resources/cpu/max")
+	assert.Contains(t, fields, "/name")
+	assert.NotContains(t, fields, "/resources")
+}
@@ -0,0 +1,44 @@
+package deploymentspec
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/skatteetaten/ao/pkg/auroraconfig"
+)
+
+// Diff returns the fields with different values in two deployment specs, sorted by field. Values are
+// compared as JSON, and a field missing from one of the specs has an empty value in the change.
+func Diff(before, after DeploymentSpec) []auroraconfig.ValueChange {
+	values := func(spec DeploymentSpec) map[string]string {
+		encoded := make(map[string]string)
+		for _, field := range spec.Fields() {
+			data, err := json.Marshal(spec.Get(field))
+			if err != nil {
+				data = []byte(spec.GetString(field))
+			}
+			encoded[field] = string(data)
+		}
+		return encoded
+	}
+	beforeValues, afterValues := values(before), values(after)
+
+	var fields []string
+	for field := range beforeValues {
+		fields = append(fields, field)
+	}
+	for field := range afterValues {
+		if _, exists := beforeValues[field]; !exists {
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+
+	var changes []auroraconfig.ValueChange
+	for _, field := range fields {
+		if beforeValues[field] != afterValues[field] {
+			changes = append(changes, auroraconfig.ValueChange{Path: field, Before: beforeValues[field], After: afterValues[field]})
+		}
+	}
+	return changes
+}
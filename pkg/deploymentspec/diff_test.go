@@ -0,0 +1,31 @@
+package deploymentspec
+
+import (
+	"testing"
+
+	"github.com/skatteetaten/ao/pkg/auroraconfig"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	before, err := NewRenderer([]auroraconfig.AuroraConfigFile{
+		{Name: "crm.json", Contents: `{"version": "1.0.0", "replicas": 2}`},
+		{Name: "utv/crm.json", Contents: `{}`},
+	}).Render("utv/crm")
+	assert.NoError(t, err)
+
+	after, err := NewRenderer([]auroraconfig.AuroraConfigFile{
+		{Name: "crm.json", Contents: `{"version": "1.1.0", "config": {"LOG_LEVEL": "DEBUG"}}`},
+		{Name: "utv/crm.json", Contents: `{}`},
+	}).Render("utv/crm")
+	assert.NoError(t, err)
+
+	assert.Equal(t, []auroraconfig.ValueChange{
+		{Path: "/config/LOG_LEVEL", Before: "", After: `"DEBUG"`},
+		{Path: "/replicas", Before: "2", After: ""},
+		{Path: "/version", Before: `"1.0.0"`, After: `"1.1.0"`},
+	}, Diff(before, after))
+
+	assert.Empty(t, Diff(before, before))
+	assert.Len(t, Diff(nil, after), 5)
+}
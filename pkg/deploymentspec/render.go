@@ -0,0 +1,193 @@
+package deploymentspec
+
+import (
+	"encoding/json"
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/skatteetaten/ao/pkg/auroraconfig"
+)
+
+// Sources of fields that are not read from a file
+const (
+	SourceStatic     = "static"
+	SourceFolderName = "folderName"
+	SourceFileName   = "fileName"
+)
+
+// Renderer merges local AuroraConfig files into deployment specs. It approximates Boober, but does not
+// add default values or validate the result.
+type Renderer struct {
+	fileNames auroraconfig.FileNames
+	contents  map[string]string
+	documents map[string]map[string]interface{}
+}
+
+type renderedField struct {
+	value   interface{}
+	source  string
+	sources []interface{}
+}
+
+// NewRenderer creates a renderer for the files of an AuroraConfig
+func NewRenderer(files []auroraconfig.AuroraConfigFile) *Renderer {
+	renderer := &Renderer{
+		contents:  make(map[string]string),
+		documents: make(map[string]map[string]interface{}),
+	}
+	for _, file := range files {
+		renderer.fileNames = append(renderer.fileNames, file.Name)
+		renderer.contents[file.Name] = file.Contents
+	}
+	return renderer
+}
+
+// Layers returns the files merged into the deployment spec of an ApplicationDeploymentRef, with the
+// least specific first: about.json, the base file, the environment file and the application file.
+// The base file and environment file are changed with baseFile and envFile in the application file.
+func (r *Renderer) Layers(ref string) ([]string, error) {
+	segments := strings.Split(ref, "/")
+	if len(segments) != 2 {
+		return nil, errors.Errorf("%s is not an ApplicationDeploymentRef, must be in the form env/app", ref)
+	}
+	environment, application := segments[0], segments[1]
+
+	applicationFile, err := r.fileNames.Find(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	appDocument, err := r.document(applicationFile)
+	if err != nil {
+		return nil, err
+	}
+
+	baseName := application
+	if baseFile, ok := appDocument["baseFile"].(string); ok && baseFile != "" {
+		baseName = strings.TrimSuffix(baseFile, path.Ext(baseFile))
+	}
+	baseFile, err := r.fileNames.Find(baseName)
+	if err != nil {
+		return nil, errors.Errorf("%s has no base file %s.json", ref, baseName)
+	}
+
+	envName := "about"
+	if envFile, ok := appDocument["envFile"].(string); ok && envFile != "" {
+		envName = strings.TrimSuffix(envFile, path.Ext(envFile))
+	}
+
+	var layers []string
+	if globalFile, err := r.fileNames.Find("about"); err == nil {
+		layers = append(layers, globalFile)
+	}
+	layers = append(layers, baseFile)
+	if envFile, err := r.fileNames.Find(environment + "/" + envName); err == nil {
+		layers = append(layers, envFile)
+	}
+	return append(layers, applicationFile), nil
+}
+
+// Render merges the layers of an ApplicationDeploymentRef into a deployment spec. Every field has the
+// file that set it as source, and all the files that set it as sources.
+func (r *Renderer) Render(ref string) (DeploymentSpec, error) {
+	layers, err := r.Layers(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	segments := strings.Split(ref, "/")
+	fields := make(map[string]*renderedField)
+	set := func(pointer string, value interface{}, source string) {
+		for existing := range fields {
+			if strings.HasPrefix(existing, pointer+"/") || strings.HasPrefix(pointer, existing+"/") {
+				delete(fields, existing)
+			}
+		}
+
+		field, exists := fields[pointer]
+		if !exists {
+			field = &renderedField{}
+			fields[pointer] = field
+		}
+		field.value, field.source = value, source
+		field.sources = append(field.sources, map[string]interface{}{"name": source, "value": value})
+	}
+
+	set("/applicationDeploymentRef", ref, SourceStatic)
+	set("/envName", segments[0], SourceFolderName)
+	set("/name", segments[1], SourceFileName)
+
+	for _, layer := range layers {
+		document, err := r.document(layer)
+		if err != nil {
+			return nil, err
+		}
+		flatten("", document, func(pointer string, value interface{}) {
+			set(pointer, value, layer)
+		})
+	}
+
+	spec := make(DeploymentSpec)
+	for pointer, field := range fields {
+		current := map[string]interface{}(spec)
+		keys := strings.Split(strings.TrimPrefix(pointer, "/"), "/")
+		for _, key := range keys[:len(keys)-1] {
+			next, ok := current[key].(map[string]interface{})
+			if !ok {
+				next = make(map[string]interface{})
+				current[key] = next
+			}
+			current = next
+		}
+		current[keys[len(keys)-1]] = map[string]interface{}{
+			"source":  field.source,
+			"value":   field.value,
+			"sources": field.sources,
+		}
+	}
+
+	return spec, nil
+}
+
+// document returns the parsed contents of a file, with values as decoded by encoding/json
+func (r *Renderer) document(fileName string) (map[string]interface{}, error) {
+	if document, exists := r.documents[fileName]; exists {
+		return document, nil
+	}
+
+	node, err := auroraconfig.ParseDocument(fileName, r.contents[fileName])
+	if err != nil {
+		return nil, errors.Wrapf(err, "Could not read %s", fileName)
+	}
+
+	var value interface{}
+	if err := node.Decode(&value); err != nil {
+		return nil, errors.Wrapf(err, "Could not read %s", fileName)
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Could not read %s", fileName)
+	}
+
+	var document map[string]interface{}
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, errors.Errorf("Could not read %s: must be an object", fileName)
+	}
+
+	r.documents[fileName] = document
+	return document, nil
+}
+
+// flatten calls set for every field in document that is not a non-empty object
+func flatten(prefix string, document map[string]interface{}, set func(pointer string, value interface{})) {
+	for key, value := range document {
+		pointer := prefix + "/" + key
+		if object, ok := value.(map[string]interface{}); ok && len(object) > 0 {
+			flatten(pointer, object, set)
+		} else {
+			set(pointer, value)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package deploymentspec
+
+import (
+	"testing"
+
+	"github.com/skatteetaten/ao/pkg/auroraconfig"
+	"github.com/stretchr/testify/assert"
+)
+
+var renderFiles = []auroraconfig.AuroraConfigFile{
+	{Name: "about.json", Contents: `{"affiliation": "paas", "cluster": "utv", "config": {"LOG_LEVEL": "INFO"}}`},
+	{Name: "crm.json", Contents: `{"groupId": "no.skatteetaten", "version": "1.0.0", "resources": {"cpu": {"min": "100m"}}}`},
+	{Name: "shared.json", Contents: `{"groupId": "no.skatteetaten.shared"}`},
+	{Name: "test/about.json", Contents: `{"cluster": "test"}`},
+	{Name: "test/about-relay.json", Contents: `{"cluster": "test-relay"}`},
+	{Name: "test/crm.yaml", Contents: "version: 1.1.0\nconfig:\n  LOG_LEVEL: DEBUG\nresources:\n  cpu: 200m\n"},
+	{Name: "test/billing.json", Contents: `{"baseFile": "shared.json", "envFile": "about-relay.json", "replicas": 2}`},
+	{Name: "test/orphan.json", Contents: `{}`},
+}
+
+func TestRenderer_Layers(t *testing.T) {
+	renderer := NewRenderer(renderFiles)
+
+	layers, err := renderer.Layers("test/crm")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"about.json", "crm.json", "test/about.json", "test/crm.yaml"}, layers)
+
+	layers, err = renderer.Layers("test/billing")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"about.json", "shared.json", "test/about-relay.json", "test/billing.json"}, layers)
+
+	_, err = renderer.Layers("test/orphan")
+	assert.EqualError(t, err, "test/orphan has no base file orphan.json")
+
+	_, err = renderer.Layers("crm")
+	assert.Error(t, err)
+}
+
+func TestRenderer_Render(t *testing.T) {
+	renderer := NewRenderer(renderFiles)
+
+	spec, err := renderer.Render("test/crm")
+	assert.NoError(t, err)
+
+	assert.Equal(t, "test", spec.Cluster())
+	assert.Equal(t, "test/about.json", spec.Source("cluster"))
+	assert.Equal(t, "test", spec.Environment())
+	assert.Equal(t, SourceFolderName, spec.Source("envName"))
+	assert.Equal(t, "crm", spec.Name())
+	assert.Equal(t, "1.1.0", spec.Version())
+	assert.Equal(t, "DEBUG", spec.GetString("config/LOG_LEVEL"))
+	assert.Equal(t, "test/crm.yaml", spec.Source("/config/LOG_LEVEL"))
+	assert.Equal(t, "200m", spec.GetString("resources/cpu"))
+	assert.Equal(t, "-", spec.GetString("resources/cpu/min"))
+
+	assert.Equal(t, []string{
+		"/affiliation",
+		"/applicationDeploymentRef",
+		"/cluster",
+		"/config/LOG_LEVEL",
+		"/envName",
+		"/groupId",
+		"/name",
+		"/resources/cpu",
+		"/version",
+	}, spec.Fields())
+
+	spec, err = renderer.Render("test/billing")
+	assert.NoError(t, err)
+	assert.Equal(t, "test-relay", spec.Cluster())
+	assert.Equal(t, "no.skatteetaten.shared", spec.GetString("groupId"))
+	assert.Equal(t, "2", spec.GetString("replicas"))
+}
@@ -0,0 +1,197 @@
+package deploymentspec
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+type operator string
+
+const (
+	opEquals    operator = "="
+	opNotEquals operator = "!="
+	opMatches   operator = "~="
+	opIn        operator = "in"
+	opNotIn     operator = "notin"
+	opTruthy    operator = "truthy"
+	opFalsy     operator = "falsy"
+)
+
+// Selector is a list of requirements a deployment spec must fulfil to be selected.
+// An empty selector matches every deployment spec.
+type Selector []requirement
+
+type requirement struct {
+	field    string
+	operator operator
+	values   []string
+	pattern  *regexp.Regexp
+}
+
+var (
+	setRequirement   = regexp.MustCompile(`^([^\s=!~()]+)\s+(in|notin)\s*\((.*)\)$`)
+	valueRequirement = regexp.MustCompile(`^([^\s=!~()]+)\s*(==|=|!=|~=)\s*(.*)$`)
+	flagRequirement  = regexp.MustCompile(`^(!?)\s*([^\s=!~()]+)$`)
+)
+
+// ParseSelector parses a comma separated list of requirements. Supported requirements are
+//
+//	field=value, field==value, field!=value  exact match on the field value
+//	field~=regex                            regular expression match on the field value
+//	field in (a,b), field notin (a,b)       match on a set of values
+//	field, !field                           field is true or false
+//
+// Fields are json pointers into the deployment spec, e.g. type, cluster or deployStrategy/type.
+func ParseSelector(expression string) (Selector, error) {
+	var selector Selector
+	for _, part := range splitRequirements(expression) {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		req, err := parseRequirement(part)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Invalid selector %q", expression)
+		}
+		selector = append(selector, *req)
+	}
+
+	return selector, nil
+}
+
+// Matches returns true if the deployment spec fulfils all requirements.
+func (s Selector) Matches(spec DeploymentSpec) bool {
+	for _, req := range s {
+		if !req.matches(spec) {
+			return false
+		}
+	}
+	return true
+}
+
+// Filter returns the deployment specs matching the selector.
+func (s Selector) Filter(specs []DeploymentSpec) []DeploymentSpec {
+	if len(s) == 0 {
+		return specs
+	}
+
+	var filtered []DeploymentSpec
+	for _, spec := range specs {
+		if s.Matches(spec) {
+			filtered = append(filtered, spec)
+		}
+	}
+	return filtered
+}
+
+func parseRequirement(part string) (*requirement, error) {
+	if match := setRequirement.FindStringSubmatch(part); match != nil {
+		var values []string
+		for _, value := range strings.Split(match[3], ",") {
+			value = strings.TrimSpace(value)
+			if value != "" {
+				values = append(values, value)
+			}
+		}
+		if len(values) == 0 {
+			return nil, errors.Errorf("%s requires at least one value", match[2])
+		}
+		return &requirement{field: match[1], operator: operator(match[2]), values: values}, nil
+	}
+
+	if match := valueRequirement.FindStringSubmatch(part); match != nil {
+		op := operator(match[2])
+		if op == "==" {
+			op = opEquals
+		}
+
+		req := &requirement{field: match[1], operator: op, values: []string{strings.TrimSpace(match[3])}}
+		if op == opMatches {
+			pattern, err := regexp.Compile(req.values[0])
+			if err != nil {
+				return nil, err
+			}
+			req.pattern = pattern
+		}
+		return req, nil
+	}
+
+	if match := flagRequirement.FindStringSubmatch(part); match != nil {
+		op := opTruthy
+		if match[1] == "!" {
+			op = opFalsy
+		}
+		return &requirement{field: match[2], operator: op}, nil
+	}
+
+	return nil, errors.Errorf("could not parse requirement %q", part)
+}
+
+func (r requirement) matches(spec DeploymentSpec) bool {
+	value := spec.GetString(r.field)
+
+	switch r.operator {
+	case opEquals:
+		return value == r.values[0]
+	case opNotEquals:
+		return value != r.values[0]
+	case opMatches:
+		return spec.HasValue(r.field) && r.pattern.MatchString(value)
+	case opIn:
+		return contains(r.values, value)
+	case opNotIn:
+		return !contains(r.values, value)
+	case opTruthy:
+		return isTruthy(spec, r.field)
+	case opFalsy:
+		return !isTruthy(spec, r.field)
+	}
+
+	return false
+}
+
+func isTruthy(spec DeploymentSpec, field string) bool {
+	if !spec.HasValue(field) {
+		return false
+	}
+
+	switch strings.ToLower(spec.GetString(field)) {
+	case "", "false", "0", "<nil>":
+		return false
+	}
+	return true
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// splitRequirements splits on commas that are not inside parentheses.
+func splitRequirements(expression string) []string {
+	var parts []string
+	depth, start := 0, 0
+	for i, r := range expression {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			if depth > 0 {
+				depth--
+			}
+		case ',':
+			if depth == 0 {
+				parts = append(parts, expression[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, expression[start:])
+}
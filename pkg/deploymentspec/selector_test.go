@@ -0,0 +1,73 @@
+package deploymentspec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_SelectorMatches(t *testing.T) {
+	deploySpec := *readTestFile(t)
+
+	cases := []struct {
+		Expression string
+		Expected   bool
+	}{
+		{"", true},
+		{"type=deploy", true},
+		{"type==deploy", true},
+		{"type=template", false},
+		{"type!=template", true},
+		{"cluster in (utv,east)", true},
+		{"cluster in (utv, test)", false},
+		{"cluster notin (utv,test)", true},
+		{"version~=^1$", true},
+		{"version~=SNAPSHOT", false},
+		{"prometheus", true},
+		{"!prometheus", false},
+		{"pause", false},
+		{"!pause", true},
+		{"!does/not/exist", true},
+		{"deployStrategy/type=rolling", true},
+		{"type=deploy,cluster in (utv,east),!pause", true},
+		{"type=deploy,cluster in (utv,test),!pause", false},
+	}
+
+	for _, tc := range cases {
+		selector, err := ParseSelector(tc.Expression)
+		assert.NoError(t, err, tc.Expression)
+		assert.Equal(t, tc.Expected, selector.Matches(deploySpec), tc.Expression)
+	}
+}
+
+func Test_SelectorFilter(t *testing.T) {
+	specs := []DeploymentSpec{
+		NewDeploymentSpec("crm", "dev", "utv", "1.0.0-SNAPSHOT"),
+		NewDeploymentSpec("erp", "dev", "utv", "1.0.0"),
+		NewDeploymentSpec("crm", "test", "test", "1.0.0-SNAPSHOT"),
+		NewDeploymentSpec("crm", "prod", "prod", "1.0.0"),
+	}
+
+	selector, err := ParseSelector("cluster in (utv,test),version~=SNAPSHOT")
+	assert.NoError(t, err)
+
+	filtered := selector.Filter(specs)
+	assert.Len(t, filtered, 2)
+	assert.Equal(t, "dev", filtered[0].Environment())
+	assert.Equal(t, "test", filtered[1].Environment())
+}
+
+func Test_ParseSelectorErrors(t *testing.T) {
+	invalid := []string{
+		"version~=[",
+		"cluster in ()",
+		"cluster in (utv",
+		"=deploy",
+		"type deploy",
+	}
+
+	for _, expression := range invalid {
+		_, err := ParseSelector(expression)
+		assert.Error(t, err, expression)
+	}
+}
@@ -0,0 +1,116 @@
+package deploymentspec
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/skatteetaten/ao/pkg/auroraconfig"
+)
+
+// Snapshot is the deployment specs of the ApplicationDeploymentRefs in an AuroraConfig at a point in time
+type Snapshot struct {
+	Name        string                    `json:"name"`
+	Affiliation string                    `json:"affiliation"`
+	RefName     string                    `json:"refName"`
+	Time        time.Time                 `json:"time"`
+	Specs       map[string]DeploymentSpec `json:"specs"`
+}
+
+// RefChanges is the difference between the deployment specs of an ApplicationDeploymentRef. Added and
+// Removed are set if there is no deployment spec before or after, otherwise Changes has the changed fields.
+type RefChanges struct {
+	Ref     string
+	Added   bool
+	Removed bool
+	Changes []auroraconfig.ValueChange
+}
+
+// NewSnapshot creates a snapshot of specs
+func NewSnapshot(name, affiliation, refName string, now time.Time, specs []DeploymentSpec) *Snapshot {
+	return &Snapshot{
+		Name:        name,
+		Affiliation: affiliation,
+		RefName:     refName,
+		Time:        now,
+		Specs:       SpecsByRef(specs),
+	}
+}
+
+// SpecsByRef returns the deployment specs by their ApplicationDeploymentRef
+func SpecsByRef(specs []DeploymentSpec) map[string]DeploymentSpec {
+	byRef := make(map[string]DeploymentSpec)
+	for _, spec := range specs {
+		ref := spec.GetString("applicationDeploymentRef")
+		if ref == "-" {
+			ref = spec.Environment() + "/" + spec.Name()
+		}
+		byRef[ref] = spec
+	}
+	return byRef
+}
+
+// LoadSnapshot reads a snapshot saved with Save
+func LoadSnapshot(path string) (*Snapshot, error) {
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, errors.Errorf("No snapshot in %s", path)
+	} else if err != nil {
+		return nil, err
+	}
+
+	var snapshot Snapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, errors.Wrapf(err, "Invalid snapshot %s", path)
+	}
+	return &snapshot, nil
+}
+
+// Save writes the snapshot to path, the folder is created if it does not exist
+func (s *Snapshot) Save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return errors.Wrap(err, "Could not create snapshot folder")
+	}
+	return ioutil.WriteFile(path, data, 0644)
+}
+
+// DiffSpecs returns the ApplicationDeploymentRefs with different deployment specs, sorted by ref
+func DiffSpecs(before, after map[string]DeploymentSpec) []RefChanges {
+	var refs []string
+	for ref := range before {
+		refs = append(refs, ref)
+	}
+	for ref := range after {
+		if _, exists := before[ref]; !exists {
+			refs = append(refs, ref)
+		}
+	}
+	sort.Strings(refs)
+
+	var changes []RefChanges
+	for _, ref := range refs {
+		beforeSpec, existedBefore := before[ref]
+		afterSpec, existsAfter := after[ref]
+
+		switch {
+		case !existedBefore:
+			changes = append(changes, RefChanges{Ref: ref, Added: true})
+		case !existsAfter:
+			changes = append(changes, RefChanges{Ref: ref, Removed: true})
+		default:
+			if diff := Diff(beforeSpec, afterSpec); len(diff) > 0 {
+				changes = append(changes, RefChanges{Ref: ref, Changes: diff})
+			}
+		}
+	}
+	return changes
+}
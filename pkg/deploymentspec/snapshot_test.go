@@ -0,0 +1,64 @@
+package deploymentspec
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/skatteetaten/ao/pkg/auroraconfig"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnapshot(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ao-snapshot")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	renderer := NewRenderer(renderFiles)
+	crm, err := renderer.Render("test/crm")
+	assert.NoError(t, err)
+	billing, err := renderer.Render("test/billing")
+	assert.NoError(t, err)
+
+	now := time.Date(2019, 11, 6, 10, 30, 0, 0, time.UTC)
+	snapshot := NewSnapshot("before", "paas", "master", now, []DeploymentSpec{crm, billing})
+	assert.Len(t, snapshot.Specs, 2)
+
+	path := filepath.Join(dir, "paas", "before.json")
+	assert.NoError(t, snapshot.Save(path))
+
+	loaded, err := LoadSnapshot(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "master", loaded.RefName)
+	assert.True(t, now.Equal(loaded.Time))
+	assert.Equal(t, "1.1.0", loaded.Specs["test/crm"].Version())
+	assert.Empty(t, DiffSpecs(snapshot.Specs, loaded.Specs))
+
+	_, err = LoadSnapshot(filepath.Join(dir, "missing.json"))
+	assert.EqualError(t, err, "No snapshot in "+filepath.Join(dir, "missing.json"))
+}
+
+func TestDiffSpecs(t *testing.T) {
+	before, err := NewRenderer(renderFiles).Render("test/crm")
+	assert.NoError(t, err)
+
+	files := append([]auroraconfig.AuroraConfigFile{}, renderFiles...)
+	files[1].Contents = `{"groupId": "no.skatteetaten", "version": "1.0.0", "resources": {"cpu": {"min": "100m"}}, "replicas": 2}`
+	after, err := NewRenderer(files).Render("test/crm")
+	assert.NoError(t, err)
+	billing, err := NewRenderer(files).Render("test/billing")
+	assert.NoError(t, err)
+
+	changes := DiffSpecs(
+		map[string]DeploymentSpec{"test/crm": before, "test/erp": before},
+		map[string]DeploymentSpec{"test/crm": after, "test/billing": billing},
+	)
+
+	assert.Equal(t, []RefChanges{
+		{Ref: "test/billing", Added: true},
+		{Ref: "test/crm", Changes: []auroraconfig.ValueChange{{Path: "/replicas", Before: "", After: "2"}}},
+		{Ref: "test/erp", Removed: true},
+	}, changes)
+}
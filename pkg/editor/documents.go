@@ -0,0 +1,195 @@
+package editor
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	documentMarker = "## ==> "
+
+	documentsPattern = `## Please edit the objects below. Each file starts with a '%s<name>' line which must not be changed.
+## Lines beginning with '##' will be ignored. Files that are unchanged or empty are not saved.
+## If an error occurs while saving, the files that failed will be reopened with the relevant failures.
+%s`
+)
+
+// Document is one of the files edited with EditDocuments
+type Document struct {
+	Name    string
+	Content string
+	OnSave  OnSaveFunc
+	// Unsaved means that Content already has unsaved changes and is saved even if it is not edited
+	Unsaved bool
+}
+
+type documentState struct {
+	Document
+	err    error
+	closed bool
+}
+
+// EditDocuments edits several files in one buffer. Each changed file is saved separately, and files that
+// fail to save are reopened with their errors until all are saved or the buffer is closed without changes.
+// The names of the saved files are returned, also when an error is returned.
+func (e Editor) EditDocuments(documents []Document) (saved []string, err error) {
+	bufferPath, err := e.createBuffer()
+	if err != nil {
+		return nil, err
+	}
+
+	var states []*documentState
+	for _, document := range documents {
+		states = append(states, &documentState{Document: document})
+	}
+
+	defer func() {
+		unsaved := false
+		for i, state := range states {
+			if !state.closed && (state.Unsaved || state.Content != documents[i].Content) {
+				unsaved = true
+			}
+		}
+		err = e.closeBuffer(bufferPath, err, unsaved)
+	}()
+
+	// A buffer that can not be split into documents is reopened as it was saved, with the error above it
+	var reopen, reopenErrors string
+	for {
+		var open []*documentState
+		for _, state := range states {
+			if !state.closed {
+				open = append(open, state)
+			}
+		}
+
+		content := reopen
+		if content == "" {
+			content = renderDocuments(open)
+		}
+		fileContent, err := e.editBuffer(bufferPath, content)
+		if err != nil {
+			return saved, err
+		}
+
+		edited, err := parseDocuments(fileContent, open)
+		if err != nil {
+			previousErrors := reopenErrors
+			reopenErrors = addErrorMessage(err.Error())
+			reopen = reopenErrors + strings.TrimPrefix(fileContent, previousErrors)
+			continue
+		}
+		reopen, reopenErrors = "", ""
+
+		changed := false
+		for _, state := range open {
+			content := edited[state.Name]
+			unsaved := state.Unsaved
+			state.Unsaved = false
+			if (content == state.Content && !unsaved) || strings.TrimSpace(content) == "" {
+				continue
+			}
+			changed = true
+			state.Content = content
+
+			if err := e.validate(state.Name, content); err != nil {
+				state.err = err
+				continue
+			}
+
+			err := state.OnSave(content)
+			if conflict, ok := err.(*ConflictError); ok {
+				state.Content = conflict.Content
+			}
+			if err != nil {
+				state.err = err
+				continue
+			}
+
+			state.closed = true
+			saved = append(saved, state.Name)
+		}
+
+		if !changed {
+			if len(saved) == 0 {
+				return nil, errors.New(cancelMessage)
+			}
+			return saved, errors.Errorf("Edit cancelled, %s not saved", strings.Join(unsavedNames(states), ", "))
+		}
+
+		if len(unsavedWithErrors(states)) == 0 {
+			return saved, nil
+		}
+
+		// Only files that failed are reopened
+		for _, state := range states {
+			if state.err == nil {
+				state.closed = true
+			}
+		}
+	}
+}
+
+// renderDocuments writes the documents with their errors, and the lines of syntax errors in the buffer
+func renderDocuments(states []*documentState) string {
+	var buffer string
+	linesBefore := linesBeforeContent(documentsPattern)
+	for _, state := range states {
+		var documentErrors string
+		if state.err != nil {
+			documentErrors = errorBlock(state.err, linesBefore+1)
+		}
+		document := fmt.Sprintf("%s%s\n%s%s\n", documentMarker, state.Name, documentErrors, state.Content)
+		buffer += document
+		linesBefore += strings.Count(document, "\n")
+	}
+	return fmt.Sprintf(documentsPattern, documentMarker, buffer)
+}
+
+// parseDocuments splits the buffer on the document markers and fails if a document is missing
+func parseDocuments(content string, states []*documentState) (map[string]string, error) {
+	documents := make(map[string]string)
+	var name string
+	var lines []string
+	for _, line := range strings.Split(content, "\n") {
+		if strings.HasPrefix(line, documentMarker) {
+			if name != "" {
+				documents[name] = stripComments(strings.Join(lines, "\n"))
+			}
+			name, lines = strings.TrimSpace(strings.TrimPrefix(line, documentMarker)), nil
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if name != "" {
+		documents[name] = stripComments(strings.Join(lines, "\n"))
+	}
+
+	for _, state := range states {
+		if _, exists := documents[state.Name]; !exists {
+			return nil, errors.Errorf("The line '%s%s' is missing, file markers must not be changed", documentMarker, state.Name)
+		}
+	}
+
+	return documents, nil
+}
+
+func unsavedNames(states []*documentState) []string {
+	var names []string
+	for _, state := range unsavedWithErrors(states) {
+		names = append(names, state.Name)
+	}
+	return names
+}
+
+func unsavedWithErrors(states []*documentState) []*documentState {
+	var unsaved []*documentState
+	for _, state := range states {
+		if !state.closed && state.err != nil {
+			unsaved = append(unsaved, state)
+		}
+	}
+	return unsaved
+}
@@ -0,0 +1,86 @@
+package editor
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEditor_EditDocuments(t *testing.T) {
+	var savedContent []string
+	failOnce := true
+	onSave := func(name string) OnSaveFunc {
+		return func(content string) error {
+			if name == "foo/bar.json" && failOnce {
+				failOnce = false
+				return errors.New("bar is not legal")
+			}
+			savedContent = append(savedContent, name+" "+content)
+			return nil
+		}
+	}
+
+	documents := []Document{
+		{Name: "foo/about.json", Content: `{"a":1}`, OnSave: onSave("foo/about.json")},
+		{Name: "foo/bar.json", Content: `{"b":1}`, OnSave: onSave("foo/bar.json")},
+		{Name: "foo/baz.yaml", Content: "c: 1", OnSave: onSave("foo/baz.yaml")},
+	}
+
+	var opened []string
+	fileEditor := NewEditor(nil)
+	fileEditor.OpenEditor = func(tempFile string) error {
+		data, err := ioutil.ReadFile(tempFile)
+		if err != nil {
+			t.Error(err)
+		}
+		buffer := string(data)
+		opened = append(opened, buffer)
+
+		switch len(opened) {
+		case 1:
+			buffer = strings.Replace(buffer, `{"a":1}`, `{"a":2}`, 1)
+			buffer = strings.Replace(buffer, `{"b":1}`, `{"b":2}`, 1)
+		case 2:
+			// Removing a marker is not allowed
+			buffer = strings.Replace(buffer, documentMarker+"foo/bar.json\n", "", 1)
+			buffer = strings.Replace(buffer, `{"b":2}`, `{"b":5}`, 1)
+		case 3:
+			buffer = strings.Replace(buffer, `{"b":5}`, documentMarker+"foo/bar.json\n"+`{"b":3,}`, 1)
+		case 4:
+			buffer = strings.Replace(buffer, `{"b":3,}`, `{"b":3}`, 1)
+		}
+		return ioutil.WriteFile(tempFile, []byte(buffer), 0700)
+	}
+
+	saved, err := fileEditor.EditDocuments(documents)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"foo/about.json", "foo/bar.json"}, saved)
+	assert.Equal(t, []string{`foo/about.json {"a":2}`, `foo/bar.json {"b":3}`}, savedContent)
+	assert.Len(t, opened, 4)
+	assert.Contains(t, opened[0], documentMarker+"foo/baz.yaml\nc: 1")
+	assert.NotContains(t, opened[1], "foo/about.json")
+	assert.NotContains(t, opened[1], "foo/baz.yaml")
+	assert.Contains(t, opened[1], fmt.Sprintf("%sfoo/bar.json\n##\n## ERROR:\n## bar is not legal\n", documentMarker))
+	assert.True(t, strings.HasPrefix(opened[2], "##\n## ERROR:\n## The line '"+documentMarker+"foo/bar.json' is missing"))
+	assert.Contains(t, opened[2], `{"b":5}`, "the edits of a buffer with a missing marker are kept")
+	assert.Contains(t, opened[3], "## Invalid JSON format at line 9, column 8")
+	assert.Equal(t, `{"b":3,}`, strings.Split(opened[3], "\n")[8])
+}
+
+func TestEditor_EditDocumentsCancel(t *testing.T) {
+	fileEditor := NewEditor(nil)
+	fileEditor.OpenEditor = func(tempFile string) error {
+		return nil
+	}
+
+	documents := []Document{{Name: "a.json", Content: "{}"}, {Name: "b.json", Content: "{}"}}
+	saved, err := fileEditor.EditDocuments(documents)
+
+	assert.Empty(t, saved)
+	assert.EqualError(t, err, cancelMessage)
+}
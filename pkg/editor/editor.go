@@ -6,19 +6,22 @@ import (
 	"io/ioutil"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"runtime"
 	"strings"
 
 	"github.com/andybalholm/crlf"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
+	"github.com/skatteetaten/ao/pkg/auroraconfig"
 	"golang.org/x/text/transform"
 )
 
 const (
-	invalidJson   = "Invalid JSON format"
 	cancelMessage = "Edit cancelled, no changes made."
 
+	unresolvedMessage = "Resolve the conflicts between <<<<<<< mine and >>>>>>> theirs before saving"
+
 	editPattern = `## Name: %s
 ## Please edit the object below. Lines beginning with '##' will be ignored,
 ## and an empty file will abort the edit. If an error occurs while saving this file will be
@@ -30,9 +33,25 @@ const (
 type (
 	OnSaveFunc func(modifiedContent string) error
 
+	// ConflictError is returned by OnSave to reopen the editor with Content instead of the saved content,
+	// e.g. a merge with changes saved by someone else
+	ConflictError struct {
+		Message string
+		Content string
+	}
+
 	Editor struct {
 		OpenEditor func(string) error
 		OnSave     OnSaveFunc
+		// CheckSchema checks JSON and YAML files against the AuroraConfig schema before they are saved
+		CheckSchema bool
+		// RecoveryDir keeps the buffer until the edit is saved, described by Recovery. If empty a temp file
+		// is used and removed when the edit ends.
+		RecoveryDir string
+		Recovery    RecoveredEdit
+		// Unsaved means that the content to edit already has unsaved changes, e.g. from a recovered edit,
+		// so closing the editor without changes saves it
+		Unsaved bool
 	}
 )
 
@@ -43,51 +62,50 @@ func NewEditor(saveFunc OnSaveFunc) *Editor {
 	}
 }
 
-func (e Editor) Edit(content string, name string) error {
+func (e Editor) Edit(content string, name string) (err error) {
 
-	tempFilePath, err := createTempFile()
+	bufferPath, err := e.createBuffer()
 	if err != nil {
 		return err
 	}
-	defer func() {
-		err := os.Remove(tempFilePath)
-		if err != nil {
-			logrus.Fatal("WARNING: Unable to delete temp file: "+err.Error()+": ", tempFilePath)
-		}
-	}()
 
 	var editErrors string
 	originalContent := content
 	currentContent := originalContent
+	unsaved := e.Unsaved
+
+	defer func() {
+		err = e.closeBuffer(bufferPath, err, unsaved || currentContent != originalContent)
+	}()
 
 	var done bool
 	for !done {
 		previousContent := currentContent
 		contentToEdit := fmt.Sprintf(editPattern, name, editErrors, currentContent)
-		if runtime.GOOS == "windows" {
-			contentToEdit, _, err = transform.String(crlf.ToCRLF{}, contentToEdit)
-		}
-		err = ioutil.WriteFile(tempFilePath, []byte(contentToEdit), 0700)
+		fileContent, err := e.editBuffer(bufferPath, contentToEdit)
 		if err != nil {
 			return err
 		}
 
-		err = e.OpenEditor(tempFilePath)
-		if err != nil {
-			return err
+		currentContent = stripComments(fileContent)
+		if previousContent == currentContent && !unsaved {
+			return errors.New(cancelMessage)
 		}
+		unsaved = false
 
-		fileContent, err := ioutil.ReadFile(tempFilePath)
-		if err != nil {
-			return err
+		if strings.TrimSpace(currentContent) == "" {
+			return errors.New(cancelMessage)
 		}
 
-		currentContent = stripComments(string(fileContent))
-		if previousContent == currentContent {
-			return errors.New(cancelMessage)
+		if err := e.validate(name, currentContent); err != nil {
+			editErrors = errorBlock(err, linesBeforeContent(editPattern))
+			continue
 		}
 
 		err = e.OnSave(currentContent)
+		if conflict, ok := err.(*ConflictError); ok {
+			currentContent = conflict.Content
+		}
 		if err != nil {
 			editErrors = addErrorMessage(err.Error())
 		} else {
@@ -98,6 +116,89 @@ func (e Editor) Edit(content string, name string) error {
 	return nil
 }
 
+func (e Editor) createBuffer() (string, error) {
+	if e.RecoveryDir == "" {
+		return createTempFile()
+	}
+	return createRecoveryBuffer(e.RecoveryDir, e.Recovery)
+}
+
+// closeBuffer removes the buffer, unless the edit failed with unsaved changes and can be recovered
+func (e Editor) closeBuffer(bufferPath string, err error, unsaved bool) error {
+	if e.RecoveryDir == "" {
+		if removeErr := os.Remove(bufferPath); removeErr != nil {
+			logrus.Warnf("Unable to delete temp file %s: %s", bufferPath, removeErr)
+		}
+		return err
+	}
+
+	id := filepath.Base(bufferPath)
+	if err != nil && unsaved {
+		if e.Recovery.Kind == RecoveredSecretEdit {
+			return errors.Errorf("%s\nThe unsaved changes are kept and can be recovered with --recover %s\n"+
+				"Warning: The decrypted secret is kept unencrypted in %s, and is removed after %s unless it is recovered",
+				err, id, bufferPath, recoveryMaxAge[RecoveredSecretEdit])
+		}
+		return errors.Errorf("%s\nThe unsaved changes are kept and can be recovered with --recover %s", err, id)
+	}
+
+	if removeErr := RemoveRecoveredEdit(e.RecoveryDir, id); removeErr != nil {
+		logrus.Warnf("Unable to delete edit buffer %s: %s", bufferPath, removeErr)
+	}
+	return err
+}
+
+// editBuffer opens content in the editor and returns the edited content
+func (e Editor) editBuffer(tempFilePath, content string) (string, error) {
+	var err error
+	if runtime.GOOS == "windows" {
+		content, _, err = transform.String(crlf.ToCRLF{}, content)
+	}
+	err = ioutil.WriteFile(tempFilePath, []byte(content), 0600)
+	if err != nil {
+		return "", err
+	}
+
+	err = e.OpenEditor(tempFilePath)
+	if err != nil {
+		return "", err
+	}
+
+	fileContent, err := ioutil.ReadFile(tempFilePath)
+	if err != nil {
+		return "", err
+	}
+
+	return string(fileContent), nil
+}
+
+// validate checks for unresolved conflicts and the syntax of JSON and YAML files locally,
+// the syntax of files with other extensions is not checked
+func (e Editor) validate(name, content string) error {
+	if auroraconfig.HasConflictMarkers(content) {
+		return errors.New(unresolvedMessage)
+	}
+
+	extension := strings.ToLower(filepath.Ext(name))
+	if extension != ".json" && !auroraconfig.IsYAML(name) {
+		return nil
+	}
+
+	if err := auroraconfig.ValidateSyntax(name, content); err != nil {
+		return err
+	}
+
+	if e.CheckSchema {
+		return auroraconfig.CheckSchema(name, content)
+	}
+
+	return nil
+}
+
+func (e *ConflictError) Error() string {
+	return e.Message
+}
+
 func openEditor(filename string) error {
 	var editor = os.Getenv("EDITOR")
 	if editor == "" {
@@ -165,6 +266,35 @@ func stripComments(content string) string {
 	return actualContent
 }
 
+// linesBeforeContent returns the number of lines before the errors and content in an edit pattern
+func linesBeforeContent(pattern string) int {
+	return strings.Count(pattern[:strings.LastIndex(pattern, "%s")], "\n")
+}
+
+// errorBlock formats err as comments above content that starts after linesBefore lines. The lines of
+// syntax and schema errors refer to the content without comments, so they are moved to the lines of the
+// buffer, below the error block itself.
+func errorBlock(err error, linesBefore int) string {
+	blockLines := strings.Count(addErrorMessage(err.Error()), "\n")
+	offset := linesBefore + blockLines
+
+	switch e := err.(type) {
+	case *auroraconfig.SyntaxError:
+		moved := *e
+		moved.Line += offset
+		err = &moved
+	case auroraconfig.SchemaErrors:
+		var moved auroraconfig.SchemaErrors
+		for _, schemaErr := range e {
+			schemaErr.Line += offset
+			moved = append(moved, schemaErr)
+		}
+		err = moved
+	}
+
+	return addErrorMessage(err.Error())
+}
+
 func addErrorMessage(errorMessage string) string {
 	comments := "##\n## ERROR:\n"
 	for _, line := range strings.Split(errorMessage, "\n") {
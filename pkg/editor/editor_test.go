@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"strings"
 	"testing"
 
 	"github.com/pkg/errors"
@@ -106,3 +107,89 @@ func TestStripComments(t *testing.T) {
 	noComments := stripComments(content)
 	assert.Equal(t, "{}", noComments)
 }
+
+func TestEditor_EditConflict(t *testing.T) {
+	merged := "{\n<<<<<<< mine\n  \"foo\": \"bar\"\n=======\n  \"foo\": \"baz\"\n>>>>>>> theirs\n}"
+
+	saves := 0
+	fileEditor := NewEditor(func(modifiedContent string) error {
+		saves++
+		if saves == 1 {
+			return &ConflictError{Message: "foo.json has been changed by someone else", Content: merged}
+		}
+		assert.Equal(t, `{"foo":"baz"}`, modifiedContent)
+		return nil
+	})
+
+	var opened []string
+	fileEditor.OpenEditor = func(tempFile string) error {
+		data, err := ioutil.ReadFile(tempFile)
+		if err != nil {
+			t.Error(err)
+		}
+		opened = append(opened, string(data))
+
+		edit := `{"foo":"bar"}`
+		switch len(opened) {
+		case 2:
+			// Saved without resolving the conflict
+			edit = merged + "\n"
+		case 3:
+			edit = `{"foo":"baz"}`
+		}
+		return ioutil.WriteFile(tempFile, []byte(fmt.Sprintf(editPattern, "foo.json", "", edit)), 0700)
+	}
+
+	err := fileEditor.Edit("{}", "foo.json")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, saves)
+	assert.Len(t, opened, 3)
+	assert.Contains(t, opened[1], "## foo.json has been changed by someone else")
+	assert.Contains(t, opened[1], merged)
+	assert.Contains(t, opened[2], "## "+unresolvedMessage)
+}
+
+func TestEditor_EditValidation(t *testing.T) {
+	saves := 0
+	fileEditor := NewEditor(func(modifiedContent string) error {
+		saves++
+		return nil
+	})
+	fileEditor.CheckSchema = true
+
+	edits := []string{"{\n  \"replicas\": 2,\n}", `{"replica": 2}`, `{"replicas": 2}`}
+	var opened []string
+	fileEditor.OpenEditor = func(tempFile string) error {
+		data, err := ioutil.ReadFile(tempFile)
+		if err != nil {
+			t.Error(err)
+		}
+		opened = append(opened, string(data))
+		edit := edits[len(opened)-1]
+		return ioutil.WriteFile(tempFile, []byte(fmt.Sprintf(editPattern, "foo.json", "", edit)), 0700)
+	}
+
+	err := fileEditor.Edit("{}", "foo.json")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, saves)
+	// Lines refer to the reopened buffer, below the header and the error block
+	assert.Contains(t, opened[1], "## Invalid JSON format at line 11, column 1: invalid character '}' looking for beginning of object key string")
+	assert.Equal(t, "}", strings.Split(opened[1], "\n")[10])
+	assert.Contains(t, opened[2], "## line 9, column 2: /replica is not a known field")
+	assert.Equal(t, `{"replica": 2}`, strings.Split(opened[2], "\n")[8])
+}
+
+func TestEditor_EditEmpty(t *testing.T) {
+	fileEditor := NewEditor(func(modifiedContent string) error {
+		t.Error("Empty file should not be saved")
+		return nil
+	})
+	fileEditor.OpenEditor = func(tempFile string) error {
+		return ioutil.WriteFile(tempFile, []byte("## Name: foo.json\n\n"), 0700)
+	}
+
+	err := fileEditor.Edit("{}", "foo.json")
+	assert.EqualError(t, err, cancelMessage)
+}
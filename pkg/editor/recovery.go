@@ -0,0 +1,206 @@
+package editor
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// Kinds of recovered edits
+const (
+	RecoveredFileEdit   = "file"
+	RecoveredSecretEdit = "secret"
+)
+
+const (
+	recoveryBufferPrefix = "edit-"
+	recoveryMetadataExt  = ".json"
+)
+
+// recoveryMaxAge is how long unsaved edits are kept. Secret edits are decrypted, and are kept for a shorter time.
+var recoveryMaxAge = map[string]time.Duration{
+	RecoveredFileEdit:   7 * 24 * time.Hour,
+	RecoveredSecretEdit: 24 * time.Hour,
+}
+
+// RecoveredEdit describes an edit buffer kept in the recovery directory. The buffer is kept until the
+// edit is saved, so unsaved changes survive a crashed editor, a failed save or a dropped connection.
+type RecoveredEdit struct {
+	ID          string          `json:"-"`
+	Kind        string          `json:"kind"`
+	Affiliation string          `json:"affiliation"`
+	Files       []RecoveredFile `json:"files"`
+	Started     time.Time       `json:"started"`
+}
+
+// RecoveredFile is a file in a recovered edit with the contents and ETag it was edited from
+type RecoveredFile struct {
+	Name string `json:"name"`
+	Base string `json:"base"`
+	ETag string `json:"eTag"`
+}
+
+// Names returns the names of the edited files
+func (r RecoveredEdit) Names() []string {
+	var names []string
+	for _, file := range r.Files {
+		names = append(names, file.Name)
+	}
+	return names
+}
+
+// ListRecoveredEdits returns the edits kept in dir, the most recent first
+func ListRecoveredEdits(dir string) ([]RecoveredEdit, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, recoveryBufferPrefix+"*"+recoveryMetadataExt))
+	if err != nil {
+		return nil, err
+	}
+
+	var edits []RecoveredEdit
+	for _, path := range paths {
+		edit, err := readRecoveryMetadata(path)
+		if err != nil {
+			logrus.Warnf("Ignoring recovered edit %s: %s", path, err)
+			continue
+		}
+		edits = append(edits, *edit)
+	}
+
+	sort.Slice(edits, func(i, j int) bool {
+		return edits[i].Started.After(edits[j].Started)
+	})
+
+	return edits, nil
+}
+
+// LoadRecoveredEdit returns the edit with the given id and the unsaved contents of each file in it.
+// Files that were saved before the edit was abandoned are not included.
+func LoadRecoveredEdit(dir, id string) (*RecoveredEdit, map[string]string, error) {
+	bufferPath := filepath.Join(dir, id)
+	if filepath.Base(bufferPath) != id || !strings.HasPrefix(id, recoveryBufferPrefix) {
+		return nil, nil, errors.Errorf("No recovered edit with id %s", id)
+	}
+
+	edit, err := readRecoveryMetadata(bufferPath + recoveryMetadataExt)
+	if os.IsNotExist(errors.Cause(err)) {
+		return nil, nil, errors.Errorf("No recovered edit with id %s", id)
+	} else if err != nil {
+		return nil, nil, err
+	}
+
+	data, err := ioutil.ReadFile(bufferPath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	contents := make(map[string]string)
+	if len(edit.Files) == 1 {
+		contents[edit.Files[0].Name] = stripComments(string(data))
+		return edit, contents, nil
+	}
+
+	var states []*documentState
+	for _, name := range edit.Names() {
+		if strings.Contains(string(data), documentMarker+name+"\n") {
+			states = append(states, &documentState{Document: Document{Name: name}})
+		}
+	}
+
+	contents, err = parseDocuments(string(data), states)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return edit, contents, nil
+}
+
+// RemoveExpiredEdits removes the edits in dir that have been kept longer than recoveryMaxAge
+func RemoveExpiredEdits(dir string, now time.Time) error {
+	edits, err := ListRecoveredEdits(dir)
+	if err != nil {
+		return err
+	}
+
+	for _, edit := range edits {
+		maxAge, ok := recoveryMaxAge[edit.Kind]
+		if !ok {
+			maxAge = recoveryMaxAge[RecoveredSecretEdit]
+		}
+		if now.Sub(edit.Started) <= maxAge {
+			continue
+		}
+		if err := RemoveRecoveredEdit(dir, edit.ID); err != nil {
+			return errors.Wrapf(err, "Unable to remove expired edit %s", edit.ID)
+		}
+	}
+
+	return nil
+}
+
+// RemoveRecoveredEdit removes the buffer of an edit from dir
+func RemoveRecoveredEdit(dir, id string) error {
+	bufferPath := filepath.Join(dir, id)
+	if err := os.Remove(bufferPath + recoveryMetadataExt); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err := os.Remove(bufferPath); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func readRecoveryMetadata(path string) (*RecoveredEdit, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var edit RecoveredEdit
+	if err := json.Unmarshal(data, &edit); err != nil {
+		return nil, err
+	}
+	edit.ID = strings.TrimSuffix(filepath.Base(path), recoveryMetadataExt)
+
+	return &edit, nil
+}
+
+// createRecoveryBuffer creates an empty buffer in dir and writes the metadata of the edit next to it
+func createRecoveryBuffer(dir string, edit RecoveredEdit) (string, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", errors.Wrap(err, "Unable to create recovery directory")
+	}
+	if err := RemoveExpiredEdits(dir, time.Now()); err != nil {
+		logrus.Warnf("Unable to remove expired edits: %s", err)
+	}
+
+	buffer, err := ioutil.TempFile(dir, recoveryBufferPrefix)
+	if err != nil {
+		return "", errors.Wrap(err, "Unable to create edit buffer")
+	}
+	if err := buffer.Close(); err != nil {
+		return "", errors.Wrap(err, "Unable to close edit buffer")
+	}
+
+	if edit.Started.IsZero() {
+		edit.Started = time.Now()
+	}
+
+	data, err := json.MarshalIndent(edit, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	if err := ioutil.WriteFile(buffer.Name()+recoveryMetadataExt, data, 0600); err != nil {
+		os.Remove(buffer.Name())
+		return "", errors.Wrap(err, "Unable to write edit metadata")
+	}
+
+	return buffer.Name(), nil
+}
@@ -0,0 +1,188 @@
+package editor
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func newRecoveryEditor(dir string, edits ...string) *Editor {
+	fileEditor := NewEditor(func(modifiedContent string) error {
+		return errors.New("Error connecting to api")
+	})
+	fileEditor.RecoveryDir = dir
+	fileEditor.Recovery = RecoveredEdit{
+		Kind:        RecoveredFileEdit,
+		Affiliation: "paas",
+		Files:       []RecoveredFile{{Name: "foo.json", Base: "{}", ETag: "1"}},
+	}
+
+	opened := 0
+	fileEditor.OpenEditor = func(tempFile string) error {
+		if opened >= len(edits) {
+			return nil
+		}
+		edit := edits[opened]
+		opened++
+		return ioutil.WriteFile(tempFile, []byte(fmt.Sprintf(editPattern, "foo.json", "", edit)), 0700)
+	}
+
+	return fileEditor
+}
+
+func TestEditor_EditRecovery(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ao-recovery")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	t.Run("Should keep unsaved edit when save fails", func(t *testing.T) {
+		fileEditor := newRecoveryEditor(dir, `{"a": 1}`)
+
+		editErr := fileEditor.Edit("{}", "foo.json")
+
+		edits, err := ListRecoveredEdits(dir)
+		assert.NoError(t, err)
+		if !assert.Len(t, edits, 1) {
+			return
+		}
+		assert.EqualError(t, editErr, cancelMessage+"\nThe unsaved changes are kept and can be recovered with --recover "+edits[0].ID)
+		assert.Equal(t, "paas", edits[0].Affiliation)
+		assert.Equal(t, []string{"foo.json"}, edits[0].Names())
+
+		edit, contents, err := LoadRecoveredEdit(dir, edits[0].ID)
+		assert.NoError(t, err)
+		assert.Equal(t, "1", edit.Files[0].ETag)
+		assert.Equal(t, map[string]string{"foo.json": `{"a": 1}`}, contents)
+
+		// Saving a recovered edit unchanged
+		saved := ""
+		fileEditor = newRecoveryEditor(dir)
+		fileEditor.Unsaved = true
+		fileEditor.OnSave = func(modifiedContent string) error {
+			saved = modifiedContent
+			return nil
+		}
+		fileEditor.OpenEditor = func(tempFile string) error {
+			data, _ := ioutil.ReadFile(tempFile)
+			return ioutil.WriteFile(tempFile, data, 0700)
+		}
+
+		err = fileEditor.Edit(contents["foo.json"], "foo.json")
+		assert.NoError(t, err)
+		assert.Equal(t, `{"a": 1}`, saved)
+
+		assert.NoError(t, RemoveRecoveredEdit(dir, edit.ID))
+		edits, err = ListRecoveredEdits(dir)
+		assert.NoError(t, err)
+		assert.Empty(t, edits)
+	})
+
+	t.Run("Should remove buffer when nothing is changed", func(t *testing.T) {
+		fileEditor := newRecoveryEditor(dir)
+
+		err := fileEditor.Edit("{}", "foo.json")
+
+		assert.EqualError(t, err, cancelMessage)
+		edits, _ := ListRecoveredEdits(dir)
+		assert.Empty(t, edits)
+	})
+
+	t.Run("Should keep failed documents of a multi file edit", func(t *testing.T) {
+		fileEditor := NewEditor(nil)
+		fileEditor.RecoveryDir = dir
+		fileEditor.Recovery = RecoveredEdit{Kind: RecoveredFileEdit, Files: []RecoveredFile{{Name: "a.json"}, {Name: "b.json"}}}
+		fileEditor.OpenEditor = func(tempFile string) error {
+			data, _ := ioutil.ReadFile(tempFile)
+			edited := strings.Replace(string(data), "{}", `{"x": 1}`, -1)
+			return ioutil.WriteFile(tempFile, []byte(edited), 0700)
+		}
+
+		documents := []Document{
+			{Name: "a.json", Content: "{}", OnSave: func(string) error { return nil }},
+			{Name: "b.json", Content: "{}", OnSave: func(string) error { return errors.New("failed") }},
+		}
+
+		saved, err := fileEditor.EditDocuments(documents)
+
+		assert.Equal(t, []string{"a.json"}, saved)
+		assert.Error(t, err)
+
+		edits, _ := ListRecoveredEdits(dir)
+		if assert.Len(t, edits, 1) {
+			_, contents, err := LoadRecoveredEdit(dir, edits[0].ID)
+			assert.NoError(t, err)
+			assert.Equal(t, map[string]string{"b.json": `{"x": 1}`}, contents)
+		}
+	})
+
+	t.Run("Should warn that an unsaved secret is kept decrypted", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "ao-recovery")
+		assert.NoError(t, err)
+		defer os.RemoveAll(dir)
+
+		secretEditor := newRecoveryEditor(dir, `{"a": 1}`)
+		secretEditor.Recovery.Kind = RecoveredSecretEdit
+
+		editErr := secretEditor.Edit("{}", "foo.json")
+
+		edits, err := ListRecoveredEdits(dir)
+		assert.NoError(t, err)
+		if !assert.Len(t, edits, 1) {
+			return
+		}
+		bufferPath := filepath.Join(dir, edits[0].ID)
+		assert.EqualError(t, editErr, cancelMessage+"\nThe unsaved changes are kept and can be recovered with --recover "+edits[0].ID+
+			"\nWarning: The decrypted secret is kept unencrypted in "+bufferPath+", and is removed after 24h0m0s unless it is recovered")
+
+		if runtime.GOOS != "windows" {
+			info, err := os.Stat(bufferPath)
+			assert.NoError(t, err)
+			assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+		}
+	})
+
+	_, _, err = LoadRecoveredEdit(dir, "../foo")
+	assert.EqualError(t, err, "No recovered edit with id ../foo")
+}
+
+func TestRemoveExpiredEdits(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ao-recovery")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	now := time.Now()
+	for _, edit := range []RecoveredEdit{
+		{Kind: RecoveredFileEdit, Started: now.Add(-25 * time.Hour)},
+		{Kind: RecoveredSecretEdit, Started: now.Add(-time.Hour)},
+		{Kind: RecoveredSecretEdit, Started: now.Add(-25 * time.Hour)},
+	} {
+		_, err := createRecoveryBuffer(dir, edit)
+		assert.NoError(t, err)
+	}
+
+	assert.NoError(t, RemoveExpiredEdits(dir, now))
+
+	edits, err := ListRecoveredEdits(dir)
+	assert.NoError(t, err)
+	if assert.Len(t, edits, 2) {
+		assert.Equal(t, RecoveredSecretEdit, edits[0].Kind)
+		assert.Equal(t, RecoveredFileEdit, edits[1].Kind)
+	}
+
+	assert.NoError(t, RemoveExpiredEdits(dir, now.Add(7*24*time.Hour)))
+	edits, err = ListRecoveredEdits(dir)
+	assert.NoError(t, err)
+	assert.Empty(t, edits)
+
+	files, err := ioutil.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Empty(t, files)
+}
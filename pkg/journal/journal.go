@@ -0,0 +1,234 @@
+package journal
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/skatteetaten/ao/pkg/auroraconfig"
+)
+
+// Actions recorded in the journal
+const (
+	ActionDeploy         = "deploy"
+	ActionDelete         = "delete"
+	ActionPolicyOverride = "override-policy"
+)
+
+// Statuses recorded in the journal
+const (
+	StatusDeployed = "Deployed"
+	StatusDeleted  = "Deleted"
+	StatusFailed   = "Failed"
+	// StatusOverridden marks a deploy policy violation that was overridden
+	StatusOverridden = "Overridden"
+)
+
+var dayDuration = regexp.MustCompile(`^(\d+)d$`)
+
+// Entry is a single deploy or delete outcome.
+type Entry struct {
+	Time        time.Time `json:"time"`
+	User        string    `json:"user"`
+	Action      string    `json:"action"`
+	Affiliation string    `json:"affiliation"`
+	RefName     string    `json:"refName"`
+	Cluster     string    `json:"cluster"`
+	Environment string    `json:"environment"`
+	Application string    `json:"application"`
+	Version     string    `json:"version"`
+	DeployId    string    `json:"deployId"`
+	Status      string    `json:"status"`
+	Reason      string    `json:"reason"`
+}
+
+// Journal is an append only log of deploy and delete outcomes stored as one JSON document per line.
+type Journal struct {
+	Path string
+}
+
+// Filter selects entries from the journal. Empty fields match everything.
+type Filter struct {
+	Affiliation string
+	Search      string
+	Since       time.Time
+}
+
+// NewJournal creates a journal backed by the file at the given path.
+func NewJournal(path string) *Journal {
+	return &Journal{
+		Path: path,
+	}
+}
+
+// ApplicationDeploymentRef returns the environment/application reference of the entry.
+func (e Entry) ApplicationDeploymentRef() string {
+	return e.Environment + "/" + e.Application
+}
+
+// HasDeployId returns true if the entry has a deploy id that can be inspected.
+func (e Entry) HasDeployId() bool {
+	return e.DeployId != "" && e.DeployId != "-"
+}
+
+// Append writes the given entries to the end of the journal, creating the file if needed.
+func (j *Journal) Append(entries ...Entry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(j.Path), 0700); err != nil {
+		return errors.Wrap(err, "Could not create journal directory")
+	}
+
+	file, err := os.OpenFile(j.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return errors.Wrap(err, "Could not open journal")
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	for _, entry := range entries {
+		if err := encoder.Encode(entry); err != nil {
+			return errors.Wrap(err, "Could not write to journal")
+		}
+	}
+
+	return nil
+}
+
+// Read returns all entries in the journal, oldest first. A missing journal is treated as empty.
+func (j *Journal) Read() ([]Entry, error) {
+	file, err := os.Open(j.Path)
+	if os.IsNotExist(err) {
+		return []Entry{}, nil
+	} else if err != nil {
+		return nil, errors.Wrap(err, "Could not open journal")
+	}
+	defer file.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(file)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		var entry Entry
+		if err := json.Unmarshal([]byte(text), &entry); err != nil {
+			return nil, errors.Wrapf(err, "Corrupt journal entry at line %d in %s", line, j.Path)
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(entries, func(i, k int) bool {
+		return entries[i].Time.Before(entries[k].Time)
+	})
+
+	return entries, nil
+}
+
+// Query returns all entries matching the filter, oldest first.
+func (j *Journal) Query(filter Filter) ([]Entry, error) {
+	entries, err := j.Read()
+	if err != nil {
+		return nil, err
+	}
+
+	return filter.Apply(entries), nil
+}
+
+// Last returns the most recent deploy matching the filter that has a deploy id.
+func (j *Journal) Last(filter Filter) (*Entry, error) {
+	entries, err := j.Query(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := len(entries) - 1; i >= 0; i-- {
+		if entries[i].Action == ActionDeploy && entries[i].HasDeployId() {
+			return &entries[i], nil
+		}
+	}
+
+	return nil, errors.Errorf("No deploys found in journal for %s", filter.Search)
+}
+
+// Apply returns the entries matching the filter. The search is matched against
+// environment/application the same way as when selecting applications to deploy.
+func (f Filter) Apply(entries []Entry) []Entry {
+	var refs []string
+	if f.Search != "" {
+		unique := make(map[string]bool)
+		for _, entry := range entries {
+			ref := entry.ApplicationDeploymentRef()
+			if !unique[ref] {
+				unique[ref] = true
+				refs = append(refs, ref)
+			}
+		}
+		sort.Strings(refs)
+		refs = auroraconfig.SearchForApplications(f.Search, refs)
+	}
+
+	matchesRef := make(map[string]bool)
+	for _, ref := range refs {
+		matchesRef[ref] = true
+	}
+
+	filtered := []Entry{}
+	for _, entry := range entries {
+		if f.Affiliation != "" && entry.Affiliation != f.Affiliation {
+			continue
+		}
+		if !f.Since.IsZero() && entry.Time.Before(f.Since) {
+			continue
+		}
+		if f.Search != "" && !matchesRef[entry.ApplicationDeploymentRef()] {
+			continue
+		}
+		filtered = append(filtered, entry)
+	}
+
+	return filtered
+}
+
+// ParseSince parses a point in time relative to now. Accepts durations (90m, 24h, 7d),
+// dates (2006-01-02) and RFC3339 timestamps.
+func ParseSince(value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	if match := dayDuration.FindStringSubmatch(value); match != nil {
+		days, _ := strconv.Atoi(match[1])
+		return now.AddDate(0, 0, -days), nil
+	}
+
+	if duration, err := time.ParseDuration(value); err == nil {
+		return now.Add(-duration), nil
+	}
+
+	if date, err := time.ParseInLocation("2006-01-02", value, now.Location()); err == nil {
+		return date, nil
+	}
+
+	if timestamp, err := time.Parse(time.RFC3339, value); err == nil {
+		return timestamp, nil
+	}
+
+	return time.Time{}, errors.Errorf("Invalid value for since: %s. Use a duration (24h, 7d), a date (2006-01-02) or an RFC3339 timestamp", value)
+}
@@ -0,0 +1,143 @@
+package journal
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var now = time.Date(2019, 11, 27, 12, 0, 0, 0, time.UTC)
+
+func newTestJournal(t *testing.T) (*Journal, func()) {
+	dir, err := ioutil.TempDir("", "ao_journal")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return NewJournal(filepath.Join(dir, "nested", "journal.jsonl")), func() {
+		os.RemoveAll(dir)
+	}
+}
+
+func newTestEntry(affiliation, env, app, deployID string, at time.Time) Entry {
+	return Entry{
+		Time:        at,
+		User:        "tester",
+		Action:      ActionDeploy,
+		Affiliation: affiliation,
+		Cluster:     "utv",
+		Environment: env,
+		Application: app,
+		Version:     "1",
+		DeployId:    deployID,
+		Status:      StatusDeployed,
+	}
+}
+
+func TestJournal_AppendAndRead(t *testing.T) {
+	j, cleanup := newTestJournal(t)
+	defer cleanup()
+
+	entries, err := j.Read()
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+
+	err = j.Append(
+		newTestEntry("paas", "dev", "crm", "b", now),
+		newTestEntry("paas", "dev", "erp", "a", now.Add(-time.Hour)),
+	)
+	assert.NoError(t, err)
+
+	err = j.Append(newTestEntry("paas", "test", "crm", "c", now.Add(time.Hour)))
+	assert.NoError(t, err)
+
+	entries, err = j.Read()
+	assert.NoError(t, err)
+	assert.Len(t, entries, 3)
+	assert.Equal(t, "a", entries[0].DeployId)
+	assert.Equal(t, "b", entries[1].DeployId)
+	assert.Equal(t, "c", entries[2].DeployId)
+}
+
+func TestFilter_Apply(t *testing.T) {
+	entries := []Entry{
+		newTestEntry("paas", "dev", "crm", "1", now.Add(-48*time.Hour)),
+		newTestEntry("paas", "dev", "erp", "2", now.Add(-2*time.Hour)),
+		newTestEntry("paas", "test", "crm", "3", now.Add(-time.Hour)),
+		newTestEntry("sales", "dev", "crm", "4", now),
+	}
+
+	cases := []struct {
+		Filter   Filter
+		Expected []string
+	}{
+		{Filter{}, []string{"1", "2", "3", "4"}},
+		{Filter{Affiliation: "paas"}, []string{"1", "2", "3"}},
+		{Filter{Affiliation: "paas", Search: "crm"}, []string{"1", "3"}},
+		{Filter{Affiliation: "paas", Search: "dev"}, []string{"1", "2"}},
+		{Filter{Affiliation: "paas", Search: "dev/crm"}, []string{"1"}},
+		{Filter{Affiliation: "paas", Since: now.Add(-24 * time.Hour)}, []string{"2", "3"}},
+		{Filter{Affiliation: "paas", Search: "nothing"}, []string{}},
+	}
+
+	for _, tc := range cases {
+		actual := []string{}
+		for _, entry := range tc.Filter.Apply(entries) {
+			actual = append(actual, entry.DeployId)
+		}
+		assert.Equal(t, tc.Expected, actual)
+	}
+}
+
+func TestJournal_Last(t *testing.T) {
+	j, cleanup := newTestJournal(t)
+	defer cleanup()
+
+	failed := newTestEntry("paas", "dev", "crm", "-", now)
+	failed.Status = StatusFailed
+
+	deleted := newTestEntry("paas", "dev", "crm", "-", now.Add(time.Hour))
+	deleted.Action = ActionDelete
+
+	err := j.Append(
+		newTestEntry("paas", "dev", "crm", "first", now.Add(-time.Hour)),
+		failed,
+		deleted,
+		newTestEntry("paas", "dev", "erp", "other", now.Add(2*time.Hour)),
+	)
+	assert.NoError(t, err)
+
+	entry, err := j.Last(Filter{Affiliation: "paas", Search: "dev/crm"})
+	assert.NoError(t, err)
+	assert.Equal(t, "first", entry.DeployId)
+
+	_, err = j.Last(Filter{Affiliation: "paas", Search: "prod/crm"})
+	assert.Error(t, err)
+}
+
+func TestParseSince(t *testing.T) {
+	cases := []struct {
+		Value    string
+		Expected time.Time
+	}{
+		{"", time.Time{}},
+		{"90m", now.Add(-90 * time.Minute)},
+		{"24h", now.Add(-24 * time.Hour)},
+		{"7d", now.AddDate(0, 0, -7)},
+		{"2019-11-01", time.Date(2019, 11, 1, 0, 0, 0, 0, time.UTC)},
+		{"2019-11-01T10:00:00Z", time.Date(2019, 11, 1, 10, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tc := range cases {
+		actual, err := ParseSince(tc.Value, now)
+		assert.NoError(t, err)
+		assert.True(t, tc.Expected.Equal(actual), tc.Value)
+	}
+
+	_, err := ParseSince("yesterday", now)
+	assert.Error(t, err)
+}
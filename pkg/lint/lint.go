@@ -0,0 +1,377 @@
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/skatteetaten/ao/pkg/auroraconfig"
+	"gopkg.in/yaml.v3"
+)
+
+// FileName is the name of the lint configuration read from the root of the AuroraConfig
+const FileName = "ao-lint.json"
+
+// Severity of a rule. Problems found by rules that are off are not reported.
+type Severity string
+
+// Severities
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityOff     Severity = "off"
+)
+
+// Rules
+const (
+	RuleSyntax          = "syntax"
+	RuleDuplicateKey    = "duplicate-key"
+	RuleUnknownField    = "unknown-field"
+	RuleMissingBaseFile = "missing-base-file"
+	RuleMissingEnvAbout = "missing-env-about"
+	RuleNaming          = "naming"
+)
+
+// DefaultSeverities is the severity of every rule unless it is changed in the configuration
+var DefaultSeverities = map[string]Severity{
+	RuleSyntax:          SeverityError,
+	RuleDuplicateKey:    SeverityError,
+	RuleUnknownField:    SeverityError,
+	RuleMissingBaseFile: SeverityError,
+	RuleMissingEnvAbout: SeverityError,
+	RuleNaming:          SeverityWarning,
+}
+
+// name is the format of environment and application names, they are used in OpenShift resource names
+var name = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+// Config changes the severity of rules and ignores files matching the patterns in Ignore
+type Config struct {
+	Rules  map[string]Severity `json:"rules,omitempty"`
+	Ignore []string            `json:"ignore,omitempty"`
+}
+
+// Problem is a rule violation in a file. Line and Column are 0 if the position is unknown.
+type Problem struct {
+	File     string   `json:"file"`
+	Line     int      `json:"line,omitempty"`
+	Column   int      `json:"column,omitempty"`
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+}
+
+// Problems is the outcome of linting an AuroraConfig
+type Problems []Problem
+
+// Parse reads a lint configuration on the form {"rules": {"naming": "off"}, "ignore": ["templates/*"]}
+func Parse(data []byte) (*Config, error) {
+	var config Config
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, errors.Wrap(err, "Invalid lint configuration")
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &config, nil
+}
+
+// ParseRules parses rule severities given as rule=severity
+func ParseRules(values []string) (map[string]Severity, error) {
+	rules := make(map[string]Severity)
+	for _, value := range values {
+		parts := strings.SplitN(value, "=", 2)
+		if len(parts) != 2 {
+			return nil, errors.Errorf("Invalid rule %s, must be in the form rule=severity", value)
+		}
+		rules[parts[0]] = Severity(strings.ToLower(parts[1]))
+	}
+
+	config := Config{Rules: rules}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// Validate checks that the rules exist and that the severities and ignore patterns are valid
+func (c Config) Validate() error {
+	for rule, severity := range c.Rules {
+		if _, exists := DefaultSeverities[rule]; !exists {
+			return errors.Errorf("Unknown lint rule %s, must be one of [%s]", rule, strings.Join(ruleNames(), ", "))
+		}
+		if severity != SeverityError && severity != SeverityWarning && severity != SeverityOff {
+			return errors.Errorf("Unknown severity %s for lint rule %s, must be one of [%s, %s, %s]", severity, rule, SeverityError, SeverityWarning, SeverityOff)
+		}
+	}
+
+	for _, pattern := range c.Ignore {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return errors.Wrapf(err, "Invalid ignore pattern %s", pattern)
+		}
+	}
+
+	return nil
+}
+
+// Severity returns the configured severity of the rule
+func (c Config) Severity(rule string) Severity {
+	if severity, exists := c.Rules[rule]; exists {
+		return severity
+	}
+	return DefaultSeverities[rule]
+}
+
+func (c Config) ignores(fileName string) bool {
+	for _, segment := range strings.Split(fileName, "/") {
+		if strings.HasPrefix(segment, ".") {
+			return true
+		}
+	}
+
+	if auroraconfig.IsToolFile(fileName) {
+		return true
+	}
+
+	for _, pattern := range c.Ignore {
+		if matched, _ := path.Match(pattern, fileName); matched {
+			return true
+		}
+	}
+	return false
+}
+
+func (p Problem) String() string {
+	position := p.File
+	if p.Line > 0 {
+		position += fmt.Sprintf(":%d", p.Line)
+		if p.Column > 0 {
+			position += fmt.Sprintf(":%d", p.Column)
+		}
+	}
+	return fmt.Sprintf("%s: %s: %s (%s)", position, p.Severity, p.Message, p.Rule)
+}
+
+// Count returns the number of problems with the given severity
+func (p Problems) Count(severity Severity) int {
+	count := 0
+	for _, problem := range p {
+		if problem.Severity == severity {
+			count++
+		}
+	}
+	return count
+}
+
+// Lint checks the files of an AuroraConfig without connecting to Boober. The problems are sorted
+// by file and position.
+func Lint(files []auroraconfig.AuroraConfigFile, config Config) Problems {
+	linter := &linter{config: config, documents: make(map[string]*yaml.Node)}
+
+	for _, file := range files {
+		if config.ignores(filepath.ToSlash(file.Name)) {
+			continue
+		}
+		linter.lintFile(filepath.ToSlash(file.Name), file.Contents)
+	}
+	linter.lintStructure()
+
+	problems := linter.problems
+	sort.SliceStable(problems, func(i, j int) bool {
+		if problems[i].File != problems[j].File {
+			return problems[i].File < problems[j].File
+		}
+		if problems[i].Line != problems[j].Line {
+			return problems[i].Line < problems[j].Line
+		}
+		return problems[i].Column < problems[j].Column
+	})
+	return problems
+}
+
+type linter struct {
+	config    Config
+	fileNames auroraconfig.FileNames
+	documents map[string]*yaml.Node
+	problems  Problems
+}
+
+func (l *linter) report(rule, file string, line, column int, format string, args ...interface{}) {
+	severity := l.config.Severity(rule)
+	if severity == SeverityOff {
+		return
+	}
+
+	l.problems = append(l.problems, Problem{
+		File:     file,
+		Line:     line,
+		Column:   column,
+		Rule:     rule,
+		Severity: severity,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+func (l *linter) lintFile(fileName, contents string) {
+	l.fileNames = append(l.fileNames, fileName)
+	l.lintName(fileName)
+
+	document, err := auroraconfig.ParseDocument(fileName, contents)
+	if err != nil {
+		if syntaxErr, ok := err.(*auroraconfig.SyntaxError); ok {
+			l.report(RuleSyntax, fileName, syntaxErr.Line, syntaxErr.Column, "%s", syntaxErr.Message)
+		} else {
+			l.report(RuleSyntax, fileName, 0, 0, "%s", err)
+		}
+		return
+	}
+	l.documents[fileName] = document.Content[0]
+
+	l.lintDuplicateKeys(fileName, "", document.Content[0])
+
+	if err := auroraconfig.CheckSchema(fileName, contents); err != nil {
+		violations, ok := err.(auroraconfig.SchemaErrors)
+		if !ok {
+			return
+		}
+		for _, violation := range violations {
+			if violation.IsUnknownField() && strings.Count(violation.Path, "/") == 1 {
+				l.report(RuleUnknownField, fileName, violation.Line, violation.Column, "%s is not a known field", strings.TrimPrefix(violation.Path, "/"))
+			}
+		}
+	}
+}
+
+// lintDuplicateKeys reports keys defined more than once in the same object
+func (l *linter) lintDuplicateKeys(fileName, path string, node *yaml.Node) {
+	switch node.Kind {
+	case yaml.MappingNode:
+		seen := make(map[string]*yaml.Node)
+		for i := 0; i < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			fieldPath := path + "/" + key.Value
+			if first, exists := seen[key.Value]; exists {
+				l.report(RuleDuplicateKey, fileName, key.Line, key.Column, "%s is already defined at line %d", fieldPath, first.Line)
+			} else {
+				seen[key.Value] = key
+			}
+			l.lintDuplicateKeys(fileName, fieldPath, value)
+		}
+	case yaml.SequenceNode:
+		for i, item := range node.Content {
+			l.lintDuplicateKeys(fileName, fmt.Sprintf("%s/%d", path, i), item)
+		}
+	}
+}
+
+func (l *linter) lintName(fileName string) {
+	segments := strings.Split(fileName, "/")
+	if len(segments) > 2 {
+		l.report(RuleNaming, fileName, 0, 0, "files must be in the root or in an environment folder")
+		return
+	}
+
+	if len(segments) == 2 && !name.MatchString(segments[0]) {
+		l.report(RuleNaming, fileName, 0, 0, "environment %s must consist of lower case letters, digits and '-'", segments[0])
+	}
+
+	base := segments[len(segments)-1]
+	application := strings.TrimSuffix(base, filepath.Ext(base))
+	if !name.MatchString(application) {
+		l.report(RuleNaming, fileName, 0, 0, "%s must consist of lower case letters, digits and '-'", application)
+	}
+}
+
+// lintStructure checks that every environment has an about file and that every ApplicationDeploymentRef has a base file
+func (l *linter) lintStructure() {
+	for _, environment := range l.environments() {
+		if l.find(environment+"/about") != "" {
+			continue
+		}
+
+		var refs []string
+		hasEnvFile := true
+		for _, ref := range l.fileNames.GetApplicationDeploymentRefs() {
+			if strings.HasPrefix(ref, environment+"/") {
+				refs = append(refs, ref)
+				hasEnvFile = hasEnvFile && l.field(ref, "envFile") != ""
+			}
+		}
+		if len(refs) > 0 && hasEnvFile {
+			continue
+		}
+
+		l.report(RuleMissingEnvAbout, environment+"/", 0, 0, "environment %s has no about.json", environment)
+	}
+
+	for _, ref := range l.fileNames.GetApplicationDeploymentRefs() {
+		if strings.Count(ref, "/") != 1 {
+			continue
+		}
+
+		baseFile := l.field(ref, "baseFile")
+		if baseFile == "" {
+			baseFile = strings.Split(ref, "/")[1]
+		}
+		baseFile = strings.TrimSuffix(baseFile, filepath.Ext(baseFile))
+
+		if l.find(baseFile) == "" {
+			l.report(RuleMissingBaseFile, l.find(ref), 0, 0, "%s has no base file %s.json", ref, baseFile)
+		}
+	}
+}
+
+// environments returns the folders in the root of the AuroraConfig
+func (l *linter) environments() []string {
+	seen := make(map[string]bool)
+	var environments []string
+	for _, fileName := range l.fileNames {
+		segments := strings.Split(fileName, "/")
+		if len(segments) == 2 && !seen[segments[0]] {
+			seen[segments[0]] = true
+			environments = append(environments, segments[0])
+		}
+	}
+	sort.Strings(environments)
+	return environments
+}
+
+// find returns the file name of a file given without extension, or an empty string if it does not exist
+func (l *linter) find(name string) string {
+	fileName, err := l.fileNames.Find(name)
+	if err != nil {
+		return ""
+	}
+	return fileName
+}
+
+// field returns a top level string field of a file given without extension
+func (l *linter) field(name, key string) string {
+	document, exists := l.documents[l.find(name)]
+	if !exists || document.Kind != yaml.MappingNode {
+		return ""
+	}
+
+	for i := 0; i < len(document.Content); i += 2 {
+		if document.Content[i].Value == key && document.Content[i+1].Kind == yaml.ScalarNode {
+			return document.Content[i+1].Value
+		}
+	}
+	return ""
+}
+
+func ruleNames() []string {
+	var names []string
+	for rule := range DefaultSeverities {
+		names = append(names, rule)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,98 @@
+package lint
+
+import (
+	"testing"
+
+	"github.com/skatteetaten/ao/pkg/auroraconfig"
+	"github.com/stretchr/testify/assert"
+)
+
+func newFiles(files map[string]string) []auroraconfig.AuroraConfigFile {
+	var auroraConfigFiles []auroraconfig.AuroraConfigFile
+	for name, contents := range files {
+		auroraConfigFiles = append(auroraConfigFiles, auroraconfig.AuroraConfigFile{Name: name, Contents: contents})
+	}
+	return auroraConfigFiles
+}
+
+func Test_Lint(t *testing.T) {
+	files := newFiles(map[string]string{
+		"about.json":           `{"schemaVersion": "v1", "affiliation": "paas"}`,
+		"crm.json":             `{"groupId": "no.skatteetaten", "replicas": 2}`,
+		"erp.yaml":             "groupId: no.skatteetaten\nreplica: 2\n",
+		"ao-policies.json":     `{"policies": []}`,
+		".idea/misc.json":      `{`,
+		"utv/about.json":       `{"cluster": "utv"}`,
+		"utv/crm.json":         "{\n  \"version\": \"1\",\n  \"version\": \"2\"\n}",
+		"utv/erp.yaml":         "version: 1\n\treplicas: 2\n",
+		"utv/billing.json":     `{"version": "1"}`,
+		"utv/invoice.json":     `{"baseFile": "crm.json"}`,
+		"test/crm.json":        `{"envFile": "about-test.json"}`,
+		"test/about-test.json": `{"cluster": "test"}`,
+		"Prod/crm.json":        `{"version": "1"}`,
+		"prod/app/crm.json":    `{}`,
+	})
+
+	problems := Lint(files, Config{})
+
+	var actual []string
+	for _, problem := range problems {
+		actual = append(actual, problem.String())
+	}
+
+	expected := []string{
+		"Prod/: error: environment Prod has no about.json (missing-env-about)",
+		"Prod/crm.json: warning: environment Prod must consist of lower case letters, digits and '-' (naming)",
+		"erp.yaml:2:1: error: replica is not a known field (unknown-field)",
+		"prod/app/crm.json: warning: files must be in the root or in an environment folder (naming)",
+		"utv/billing.json: error: utv/billing has no base file billing.json (missing-base-file)",
+		"utv/crm.json:3:3: error: /version is already defined at line 2 (duplicate-key)",
+		"utv/erp.yaml:2: error: found a tab character that violates indentation (syntax)",
+	}
+	assert.Equal(t, expected, actual)
+	assert.Equal(t, 5, problems.Count(SeverityError))
+	assert.Equal(t, 2, problems.Count(SeverityWarning))
+
+	config := Config{
+		Rules:  map[string]Severity{RuleNaming: SeverityOff, RuleUnknownField: SeverityWarning},
+		Ignore: []string{"utv/*"},
+	}
+	problems = Lint(files, config)
+
+	actual = nil
+	for _, problem := range problems {
+		actual = append(actual, problem.String())
+	}
+	assert.Equal(t, []string{
+		"Prod/: error: environment Prod has no about.json (missing-env-about)",
+		"erp.yaml:2:1: warning: replica is not a known field (unknown-field)",
+	}, actual)
+}
+
+func Test_Parse(t *testing.T) {
+	config, err := Parse([]byte(`{"rules": {"naming": "error", "unknown-field": "off"}, "ignore": ["templates/*"]}`))
+	assert.NoError(t, err)
+	assert.Equal(t, SeverityError, config.Severity(RuleNaming))
+	assert.Equal(t, SeverityOff, config.Severity(RuleUnknownField))
+	assert.Equal(t, SeverityError, config.Severity(RuleSyntax))
+
+	invalid := []string{
+		`{"rules": {"spelling": "error"}}`,
+		`{"rules": {"naming": "fatal"}}`,
+		`{"ignore": ["[templates"]}`,
+		`{"rules": []}`,
+	}
+	for _, data := range invalid {
+		_, err := Parse([]byte(data))
+		assert.Error(t, err, data)
+	}
+}
+
+func Test_ParseRules(t *testing.T) {
+	rules, err := ParseRules([]string{"naming=off", "syntax=Warning"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]Severity{RuleNaming: SeverityOff, RuleSyntax: SeverityWarning}, rules)
+
+	_, err = ParseRules([]string{"naming"})
+	assert.EqualError(t, err, "Invalid rule naming, must be in the form rule=severity")
+}
@@ -0,0 +1,282 @@
+package notify
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// FileName is the name of the webhook file read from the root of the AuroraConfig
+const FileName = "ao-notifications.json"
+
+// Payload formats
+const (
+	FormatJSON        = "json"
+	FormatCloudEvents = "cloudevents"
+	FormatSlack       = "slack"
+	FormatTeams       = "teams"
+)
+
+// Event types
+const (
+	EventDeploy = "deploy"
+	EventDelete = "delete"
+)
+
+const defaultTextTemplate = `{{.User}} {{if eq .Type "deploy"}}deployed{{else}}deleted{{end}} {{len .Results}} application(s) in {{.Affiliation}}{{if not .Success}} ({{.Failed}} failed){{end}}
+{{range .Results}}{{if .Success}}✔{{else}}✘{{end}} {{.Cluster}} {{.Environment}}/{{.Application}}{{if ne .Version "-"}} {{.Version}}{{end}}{{if .Reason}} {{.Reason}}{{end}}
+{{end}}`
+
+// Webhook is an endpoint receiving events after deploy and delete.
+// Template is a text/template rendered with the Event. For the slack and teams formats it replaces
+// the message text, otherwise it replaces the whole request body.
+type Webhook struct {
+	Name     string            `json:"name"`
+	Url      string            `json:"url"`
+	Format   string            `json:"format,omitempty"`
+	Template string            `json:"template,omitempty"`
+	Events   []string          `json:"events,omitempty"`
+	Headers  map[string]string `json:"headers,omitempty"`
+}
+
+// Webhooks is a list of webhooks
+type Webhooks []Webhook
+
+// Event describes the outcome of a deploy or delete
+type Event struct {
+	Type        string    `json:"type"`
+	Time        time.Time `json:"time"`
+	User        string    `json:"user"`
+	Affiliation string    `json:"affiliation"`
+	RefName     string    `json:"refName"`
+	Results     []Result  `json:"results"`
+}
+
+// Result is the outcome for a single application
+type Result struct {
+	Cluster     string   `json:"cluster"`
+	Environment string   `json:"environment"`
+	Application string   `json:"application"`
+	Version     string   `json:"version"`
+	DeployId    string   `json:"deployId"`
+	Success     bool     `json:"success"`
+	Reason      string   `json:"reason,omitempty"`
+	Warnings    []string `json:"warnings,omitempty"`
+}
+
+// Success returns true if every application succeeded
+func (e Event) Success() bool {
+	return e.Failed() == 0
+}
+
+// Failed returns the number of failed applications
+func (e Event) Failed() int {
+	failed := 0
+	for _, result := range e.Results {
+		if !result.Success {
+			failed++
+		}
+	}
+	return failed
+}
+
+// Parse reads webhooks from a notification file on the form {"webhooks": [...]}
+func Parse(data []byte) (Webhooks, error) {
+	var file struct {
+		Webhooks Webhooks `json:"webhooks"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, errors.Wrap(err, "Invalid notification file")
+	}
+
+	if err := file.Webhooks.Validate(); err != nil {
+		return nil, err
+	}
+
+	return file.Webhooks, nil
+}
+
+// Validate checks url, format and template of every webhook
+func (w Webhooks) Validate() error {
+	for _, webhook := range w {
+		if webhook.Url == "" {
+			return errors.Errorf("Notification %s is missing url", webhook.displayName())
+		}
+
+		switch webhook.format() {
+		case FormatJSON, FormatCloudEvents, FormatSlack, FormatTeams:
+		default:
+			return webhook.unknownFormat()
+		}
+
+		if webhook.Template != "" {
+			if _, err := template.New(webhook.displayName()).Parse(webhook.Template); err != nil {
+				return errors.Wrapf(err, "Invalid template in notification %s", webhook.displayName())
+			}
+		}
+	}
+	return nil
+}
+
+// Send posts the event to every webhook subscribing to the event type.
+// All webhooks are attempted, and the errors of the failing ones are returned.
+func (w Webhooks) Send(httpClient *http.Client, event Event) []error {
+	var errs []error
+	for _, webhook := range w {
+		if !webhook.subscribesTo(event.Type) {
+			continue
+		}
+		if err := webhook.Send(httpClient, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// Send posts the event to the webhook
+func (w Webhook) Send(httpClient *http.Client, event Event) error {
+	payload, err := w.Payload(event)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, w.Url, bytes.NewReader(payload))
+	if err != nil {
+		return errors.Wrapf(err, "Notification %s failed", w.displayName())
+	}
+
+	contentType := "application/json"
+	if w.format() == FormatCloudEvents {
+		contentType = "application/cloudevents+json"
+	}
+	req.Header.Set("Content-Type", contentType)
+	for key, value := range w.Headers {
+		req.Header.Set(key, value)
+	}
+
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "Notification %s failed", w.displayName())
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= 300 {
+		return errors.Errorf("Notification %s failed with status %s", w.displayName(), res.Status)
+	}
+
+	return nil
+}
+
+// Payload renders the request body for the event
+func (w Webhook) Payload(event Event) ([]byte, error) {
+	switch w.format() {
+	case FormatJSON:
+		if w.Template != "" {
+			return w.render(w.Template, event)
+		}
+		return json.Marshal(event)
+	case FormatCloudEvents:
+		return json.Marshal(map[string]interface{}{
+			"specversion":     "1.0",
+			"id":              newEventID(),
+			"source":          "ao/" + event.Affiliation,
+			"type":            "no.skatteetaten.ao." + event.Type,
+			"time":            event.Time.UTC().Format(time.RFC3339),
+			"datacontenttype": "application/json",
+			"data":            event,
+		})
+	case FormatSlack:
+		text, err := w.text(event)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(map[string]interface{}{"text": text})
+	case FormatTeams:
+		text, err := w.text(event)
+		if err != nil {
+			return nil, err
+		}
+		color := "2EB886"
+		if !event.Success() {
+			color = "D50200"
+		}
+		return json.Marshal(map[string]interface{}{
+			"@type":      "MessageCard",
+			"@context":   "https://schema.org/extensions",
+			"summary":    fmt.Sprintf("ao %s in %s", event.Type, event.Affiliation),
+			"themeColor": color,
+			"text":       strings.Replace(text, "\n", "  \n", -1),
+		})
+	}
+
+	return nil, w.unknownFormat()
+}
+
+func (w Webhook) unknownFormat() error {
+	return errors.Errorf("Notification %s has unknown format %s, must be one of [%s, %s, %s, %s]",
+		w.displayName(), w.Format, FormatJSON, FormatCloudEvents, FormatSlack, FormatTeams)
+}
+
+func (w Webhook) text(event Event) (string, error) {
+	tmpl := w.Template
+	if tmpl == "" {
+		tmpl = defaultTextTemplate
+	}
+	text, err := w.render(tmpl, event)
+	return string(text), err
+}
+
+func (w Webhook) render(tmpl string, event Event) ([]byte, error) {
+	parsed, err := template.New(w.displayName()).Parse(tmpl)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Invalid template in notification %s", w.displayName())
+	}
+
+	var buf bytes.Buffer
+	if err := parsed.Execute(&buf, event); err != nil {
+		return nil, errors.Wrapf(err, "Invalid template in notification %s", w.displayName())
+	}
+	return buf.Bytes(), nil
+}
+
+func (w Webhook) subscribesTo(eventType string) bool {
+	if len(w.Events) == 0 {
+		return true
+	}
+	for _, e := range w.Events {
+		if e == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+func (w Webhook) format() string {
+	if w.Format == "" {
+		return FormatJSON
+	}
+	return w.Format
+}
+
+func (w Webhook) displayName() string {
+	if w.Name != "" {
+		return w.Name
+	}
+	return w.Url
+}
+
+func newEventID() string {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return fmt.Sprintf("%d", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(id)
+}
@@ -0,0 +1,121 @@
+package notify
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testEvent = Event{
+	Type:        EventDeploy,
+	Time:        time.Date(2019, 11, 6, 10, 30, 0, 0, time.UTC),
+	User:        "tester",
+	Affiliation: "sales",
+	RefName:     "master",
+	Results: []Result{
+		{Cluster: "utv", Environment: "dev", Application: "crm", Version: "2", DeployId: "abc", Success: true},
+		{Cluster: "utv", Environment: "dev", Application: "erp", Version: "1", DeployId: "-", Success: false, Reason: "Cluster is not reachable"},
+	},
+}
+
+func Test_Parse(t *testing.T) {
+	webhooks, err := Parse([]byte(`{"webhooks": [{"name": "team", "url": "http://hooks/team", "format": "slack", "events": ["deploy"]}]}`))
+	assert.NoError(t, err)
+	assert.Len(t, webhooks, 1)
+	assert.Equal(t, FormatSlack, webhooks[0].Format)
+
+	invalid := []string{
+		`{"webhooks": [{"name": "team"}]}`,
+		`{"webhooks": [{"url": "http://hooks/team", "format": "xml"}]}`,
+		`{"webhooks": [{"url": "http://hooks/team", "template": "{{.User"}]}`,
+		`{"webhooks": {}}`,
+	}
+	for _, data := range invalid {
+		_, err := Parse([]byte(data))
+		assert.Error(t, err, data)
+	}
+}
+
+func Test_Payload(t *testing.T) {
+	t.Run("Should default to the event as json", func(t *testing.T) {
+		payload, err := Webhook{Url: "http://hooks"}.Payload(testEvent)
+		assert.NoError(t, err)
+
+		var event Event
+		assert.NoError(t, json.Unmarshal(payload, &event))
+		assert.Equal(t, testEvent, event)
+	})
+
+	t.Run("Should wrap the event in a CloudEvent", func(t *testing.T) {
+		payload, err := Webhook{Url: "http://hooks", Format: FormatCloudEvents}.Payload(testEvent)
+		assert.NoError(t, err)
+
+		var cloudEvent map[string]interface{}
+		assert.NoError(t, json.Unmarshal(payload, &cloudEvent))
+		assert.Equal(t, "1.0", cloudEvent["specversion"])
+		assert.Equal(t, "no.skatteetaten.ao.deploy", cloudEvent["type"])
+		assert.Equal(t, "ao/sales", cloudEvent["source"])
+		assert.Equal(t, "2019-11-06T10:30:00Z", cloudEvent["time"])
+		assert.NotEmpty(t, cloudEvent["id"])
+		assert.Equal(t, "tester", cloudEvent["data"].(map[string]interface{})["user"])
+	})
+
+	t.Run("Should render a slack message", func(t *testing.T) {
+		payload, err := Webhook{Url: "http://hooks", Format: FormatSlack}.Payload(testEvent)
+		assert.NoError(t, err)
+
+		var message map[string]string
+		assert.NoError(t, json.Unmarshal(payload, &message))
+		assert.Equal(t, "tester deployed 2 application(s) in sales (1 failed)\n✔ utv dev/crm 2\n✘ utv dev/erp 1 Cluster is not reachable\n", message["text"])
+	})
+
+	t.Run("Should render a teams message card", func(t *testing.T) {
+		payload, err := Webhook{Url: "http://hooks", Format: FormatTeams, Template: "{{.User}} did {{.Type}}"}.Payload(testEvent)
+		assert.NoError(t, err)
+
+		var card map[string]string
+		assert.NoError(t, json.Unmarshal(payload, &card))
+		assert.Equal(t, "MessageCard", card["@type"])
+		assert.Equal(t, "D50200", card["themeColor"])
+		assert.Equal(t, "tester did deploy", card["text"])
+	})
+
+	t.Run("Should use template as body for json", func(t *testing.T) {
+		payload, err := Webhook{Url: "http://hooks", Template: `{"who": "{{.User}}"}`}.Payload(testEvent)
+		assert.NoError(t, err)
+		assert.Equal(t, `{"who": "tester"}`, string(payload))
+	})
+}
+
+func Test_Send(t *testing.T) {
+	var received []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		received = append(received, r.URL.Path+" "+r.Header.Get("Content-Type")+" "+r.Header.Get("X-Token"))
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		assert.NotEmpty(t, body)
+	}))
+	defer ts.Close()
+
+	webhooks := Webhooks{
+		{Name: "ok", Url: ts.URL + "/ok", Headers: map[string]string{"X-Token": "secret"}},
+		{Name: "fail", Url: ts.URL + "/fail", Format: FormatCloudEvents},
+		{Name: "deletes", Url: ts.URL + "/deletes", Events: []string{EventDelete}},
+	}
+
+	errs := webhooks.Send(ts.Client(), testEvent)
+
+	assert.Len(t, errs, 1)
+	assert.Contains(t, errs[0].Error(), "Notification fail failed with status 500")
+	assert.Equal(t, []string{
+		"/ok application/json secret",
+		"/fail application/cloudevents+json ",
+	}, received)
+}
@@ -0,0 +1,292 @@
+package policy
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/skatteetaten/ao/pkg/deploymentspec"
+)
+
+// FileName is the name of the policy file read from the root of the AuroraConfig
+const FileName = "ao-policies.json"
+
+var (
+	hourRange = regexp.MustCompile(`^(\d{2}):(\d{2})-(\d{2}):(\d{2})$`)
+	weekdays  = map[string]time.Weekday{
+		"sun": time.Sunday,
+		"mon": time.Monday,
+		"tue": time.Tuesday,
+		"wed": time.Wednesday,
+		"thu": time.Thursday,
+		"fri": time.Friday,
+		"sat": time.Saturday,
+	}
+)
+
+// Policy restricts deploys to the environments and clusters it applies to.
+// Environments and Clusters are regular expressions matched against the whole
+// envName and cluster of a deployment spec. Empty lists match everything.
+type Policy struct {
+	Name              string   `json:"name"`
+	Environments      []string `json:"environments,omitempty"`
+	Clusters          []string `json:"clusters,omitempty"`
+	Protected         bool     `json:"protected,omitempty"`
+	DisallowOverrides bool     `json:"disallowOverrides,omitempty"`
+	AllowedVersions   string   `json:"allowedVersions,omitempty"`
+	DeniedVersions    string   `json:"deniedVersions,omitempty"`
+	DeployHours       string   `json:"deployHours,omitempty"`
+	DeployDays        []string `json:"deployDays,omitempty"`
+}
+
+// Policies is a list of deploy policies. Every policy that applies to a deployment spec is evaluated.
+type Policies []Policy
+
+// Deploy describes a deploy to evaluate policies against
+type Deploy struct {
+	Specs        []deploymentspec.DeploymentSpec
+	Version      string
+	HasOverrides bool
+	Interactive  bool
+	Time         time.Time
+}
+
+// Violation is a rule in a policy broken by a deployment spec
+type Violation struct {
+	Policy      string
+	Cluster     string
+	Environment string
+	Application string
+	Message     string
+}
+
+// Report is the outcome of evaluating policies
+type Report struct {
+	Violations []Violation
+	// Protected holds the environments that must be confirmed by typing the environment name
+	Protected []string
+}
+
+type compiledPolicy struct {
+	Policy
+	environments []*regexp.Regexp
+	clusters     []*regexp.Regexp
+	allowed      *regexp.Regexp
+	denied       *regexp.Regexp
+	from, to     int
+	days         map[time.Weekday]bool
+}
+
+// Parse reads policies from a policy file on the form {"policies": [...]}
+func Parse(data []byte) (Policies, error) {
+	var file struct {
+		Policies Policies `json:"policies"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, errors.Wrap(err, "Invalid policy file")
+	}
+
+	if err := file.Policies.Validate(); err != nil {
+		return nil, err
+	}
+
+	return file.Policies, nil
+}
+
+// Validate checks that all expressions in the policies can be parsed
+func (p Policies) Validate() error {
+	_, err := p.compile()
+	return err
+}
+
+// Evaluate returns the violations and protected environments for the deploy
+func (p Policies) Evaluate(deploy Deploy) (*Report, error) {
+	compiled, err := p.compile()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{}
+	protected := make(map[string]bool)
+	for _, spec := range deploy.Specs {
+		version := spec.Version()
+		if deploy.Version != "" {
+			version = deploy.Version
+		}
+
+		for _, policy := range compiled {
+			if !policy.appliesTo(spec) {
+				continue
+			}
+
+			violate := func(format string, args ...interface{}) {
+				report.Violations = append(report.Violations, Violation{
+					Policy:      policy.Name,
+					Cluster:     spec.Cluster(),
+					Environment: spec.Environment(),
+					Application: spec.Name(),
+					Message:     fmt.Sprintf(format, args...),
+				})
+			}
+
+			if policy.Protected {
+				if deploy.Interactive {
+					protected[spec.Environment()] = true
+				} else {
+					violate("Environment is protected and requires a typed confirmation")
+				}
+			}
+
+			if policy.DisallowOverrides && deploy.HasOverrides {
+				violate("Overrides are not allowed")
+			}
+
+			if policy.allowed != nil && !policy.allowed.MatchString(version) {
+				violate("Version %s does not match %s", version, policy.AllowedVersions)
+			}
+
+			if policy.denied != nil && policy.denied.MatchString(version) {
+				violate("Version %s matches %s", version, policy.DeniedVersions)
+			}
+
+			if !policy.allowsTime(deploy.Time) {
+				violate("Deploys are only allowed %s", policy.deployWindow())
+			}
+		}
+	}
+
+	for env := range protected {
+		report.Protected = append(report.Protected, env)
+	}
+	sort.Strings(report.Protected)
+
+	return report, nil
+}
+
+func (p Policies) compile() ([]compiledPolicy, error) {
+	var compiled []compiledPolicy
+	for i, policy := range p {
+		name := policy.Name
+		if name == "" {
+			name = fmt.Sprintf("policy %d", i+1)
+		}
+
+		c := compiledPolicy{Policy: policy, from: -1, to: -1}
+		c.Name = name
+
+		var err error
+		if c.environments, err = compileAll(policy.Environments); err != nil {
+			return nil, errors.Wrapf(err, "Invalid environments in %s", name)
+		}
+		if c.clusters, err = compileAll(policy.Clusters); err != nil {
+			return nil, errors.Wrapf(err, "Invalid clusters in %s", name)
+		}
+		if policy.AllowedVersions != "" {
+			if c.allowed, err = regexp.Compile(policy.AllowedVersions); err != nil {
+				return nil, errors.Wrapf(err, "Invalid allowedVersions in %s", name)
+			}
+		}
+		if policy.DeniedVersions != "" {
+			if c.denied, err = regexp.Compile(policy.DeniedVersions); err != nil {
+				return nil, errors.Wrapf(err, "Invalid deniedVersions in %s", name)
+			}
+		}
+
+		if policy.DeployHours != "" {
+			match := hourRange.FindStringSubmatch(policy.DeployHours)
+			if match == nil {
+				return nil, errors.Errorf("Invalid deployHours %s in %s, must be in the form 08:00-16:00", policy.DeployHours, name)
+			}
+			c.from = minutes(match[1], match[2])
+			c.to = minutes(match[3], match[4])
+			if c.from < 0 || c.to < 0 {
+				return nil, errors.Errorf("Invalid deployHours %s in %s", policy.DeployHours, name)
+			}
+		}
+
+		if len(policy.DeployDays) > 0 {
+			c.days = make(map[time.Weekday]bool)
+			for _, day := range policy.DeployDays {
+				weekday, ok := weekdays[strings.ToLower(day)]
+				if !ok {
+					return nil, errors.Errorf("Invalid deployDays %s in %s, must be one of mon, tue, wed, thu, fri, sat, sun", day, name)
+				}
+				c.days[weekday] = true
+			}
+		}
+
+		compiled = append(compiled, c)
+	}
+
+	return compiled, nil
+}
+
+func (c compiledPolicy) appliesTo(spec deploymentspec.DeploymentSpec) bool {
+	return matchesAny(c.environments, spec.Environment()) && matchesAny(c.clusters, spec.Cluster())
+}
+
+func (c compiledPolicy) allowsTime(now time.Time) bool {
+	if c.days != nil && !c.days[now.Weekday()] {
+		return false
+	}
+
+	if c.from < 0 {
+		return true
+	}
+
+	current := now.Hour()*60 + now.Minute()
+	if c.from <= c.to {
+		return current >= c.from && current < c.to
+	}
+	// The window spans midnight, e.g. 22:00-02:00
+	return current >= c.from || current < c.to
+}
+
+func (c compiledPolicy) deployWindow() string {
+	var parts []string
+	if len(c.DeployDays) > 0 {
+		parts = append(parts, "on "+strings.Join(c.DeployDays, ", "))
+	}
+	if c.DeployHours != "" {
+		parts = append(parts, "between "+c.DeployHours)
+	}
+	return strings.Join(parts, " ")
+}
+
+func compileAll(patterns []string) ([]*regexp.Regexp, error) {
+	var compiled []*regexp.Regexp
+	for _, pattern := range patterns {
+		re, err := regexp.Compile("^(?:" + pattern + ")$")
+		if err != nil {
+			return nil, err
+		}
+		compiled = append(compiled, re)
+	}
+	return compiled, nil
+}
+
+func matchesAny(patterns []*regexp.Regexp, value string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, pattern := range patterns {
+		if pattern.MatchString(value) {
+			return true
+		}
+	}
+	return false
+}
+
+func minutes(hours, mins string) int {
+	var h, m int
+	fmt.Sscanf(hours, "%d", &h)
+	fmt.Sscanf(mins, "%d", &m)
+	if h > 24 || m > 59 || (h == 24 && m != 0) {
+		return -1
+	}
+	return h*60 + m
+}
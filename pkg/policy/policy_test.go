@@ -0,0 +1,104 @@
+package policy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/skatteetaten/ao/pkg/deploymentspec"
+	"github.com/stretchr/testify/assert"
+)
+
+// Wednesday
+var workingHours = time.Date(2019, 11, 6, 10, 30, 0, 0, time.Local)
+
+func Test_Parse(t *testing.T) {
+	policies, err := Parse([]byte(`{"policies": [{"name": "prod", "environments": ["prod.*"], "protected": true}]}`))
+	assert.NoError(t, err)
+	assert.Len(t, policies, 1)
+	assert.True(t, policies[0].Protected)
+
+	invalid := []string{
+		`{"policies": [{"environments": ["prod("]}]}`,
+		`{"policies": [{"deniedVersions": "*"}]}`,
+		`{"policies": [{"deployHours": "8-16"}]}`,
+		`{"policies": [{"deployHours": "08:00-25:00"}]}`,
+		`{"policies": [{"deployDays": ["monday"]}]}`,
+		`{"policies": {}}`,
+	}
+	for _, data := range invalid {
+		_, err := Parse([]byte(data))
+		assert.Error(t, err, data)
+	}
+}
+
+func Test_Evaluate(t *testing.T) {
+	specs := []deploymentspec.DeploymentSpec{
+		deploymentspec.NewDeploymentSpec("crm", "prod", "prod-relay", "1.0.0-SNAPSHOT"),
+		deploymentspec.NewDeploymentSpec("erp", "prod", "prod", "2.1.0"),
+		deploymentspec.NewDeploymentSpec("crm", "dev", "utv", "1.0.0-SNAPSHOT"),
+	}
+
+	policies := Policies{
+		{
+			Name:              "production",
+			Environments:      []string{"prod"},
+			Protected:         true,
+			DisallowOverrides: true,
+			DeniedVersions:    "SNAPSHOT",
+			DeployHours:       "08:00-15:00",
+			DeployDays:        []string{"mon", "tue", "wed", "thu"},
+		},
+	}
+
+	t.Run("Should only report protected environments for a compliant interactive deploy", func(t *testing.T) {
+		report, err := policies.Evaluate(Deploy{Specs: specs[1:], Interactive: true, Time: workingHours})
+		assert.NoError(t, err)
+		assert.Empty(t, report.Violations)
+		assert.Equal(t, []string{"prod"}, report.Protected)
+	})
+
+	t.Run("Should report every broken rule", func(t *testing.T) {
+		friday := time.Date(2019, 11, 8, 16, 0, 0, 0, time.Local)
+		report, err := policies.Evaluate(Deploy{Specs: specs, HasOverrides: true, Time: friday})
+		assert.NoError(t, err)
+		assert.Empty(t, report.Protected)
+
+		var crm []string
+		for _, violation := range report.Violations {
+			assert.Equal(t, "production", violation.Policy)
+			assert.Equal(t, "prod", violation.Environment)
+			if violation.Application == "crm" {
+				crm = append(crm, violation.Message)
+			}
+		}
+		assert.Equal(t, []string{
+			"Environment is protected and requires a typed confirmation",
+			"Overrides are not allowed",
+			"Version 1.0.0-SNAPSHOT matches SNAPSHOT",
+			"Deploys are only allowed on mon, tue, wed, thu between 08:00-15:00",
+		}, crm)
+		assert.Len(t, report.Violations, 7)
+	})
+
+	t.Run("Should evaluate the version about to be set", func(t *testing.T) {
+		allowed := Policies{{Clusters: []string{"prod.*"}, AllowedVersions: `^\d+\.\d+\.\d+$`}}
+		report, err := allowed.Evaluate(Deploy{Specs: specs[1:2], Version: "2.2.0-SNAPSHOT", Time: workingHours})
+		assert.NoError(t, err)
+		assert.Len(t, report.Violations, 1)
+		assert.Equal(t, "policy 1", report.Violations[0].Policy)
+		assert.Equal(t, `Version 2.2.0-SNAPSHOT does not match ^\d+\.\d+\.\d+$`, report.Violations[0].Message)
+	})
+}
+
+func Test_allowsTime(t *testing.T) {
+	policies, err := Policies{{DeployHours: "22:00-02:00"}}.compile()
+	assert.NoError(t, err)
+
+	at := func(hour, min int) time.Time {
+		return time.Date(2019, 11, 6, hour, min, 0, 0, time.Local)
+	}
+	assert.True(t, policies[0].allowsTime(at(23, 0)))
+	assert.True(t, policies[0].allowsTime(at(1, 59)))
+	assert.False(t, policies[0].allowsTime(at(2, 0)))
+	assert.False(t, policies[0].allowsTime(at(12, 0)))
+}
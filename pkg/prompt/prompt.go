@@ -32,3 +32,16 @@ func Confirm(message string, defaultAnswer bool) bool {
 	}
 	return update
 }
+
+func Input(message string) string {
+	p := &survey.Input{
+		Message: message,
+	}
+
+	var answer string
+	err := survey.AskOne(p, &answer, nil)
+	if err != nil {
+		logrus.Error(err)
+	}
+	return answer
+}
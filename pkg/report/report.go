@@ -0,0 +1,217 @@
+package report
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Report formats
+const (
+	FormatJUnit    = "junit"
+	FormatMarkdown = "markdown"
+)
+
+// Target is a report format and the file it is written to
+type Target struct {
+	Format string
+	Path   string
+}
+
+// Suite is a named collection of test cases, e.g. the deploys of a single ao deploy
+type Suite struct {
+	Name  string
+	Time  time.Time
+	Cases []TestCase
+}
+
+// TestCase is the outcome of a single deploy, delete or validation error
+type TestCase struct {
+	ClassName string
+	Name      string
+	Failure   string
+	Warnings  []string
+}
+
+// Failed returns true if the test case has a failure
+func (c TestCase) Failed() bool {
+	return c.Failure != ""
+}
+
+// Failures returns the number of failed test cases
+func (s Suite) Failures() int {
+	failures := 0
+	for _, c := range s.Cases {
+		if c.Failed() {
+			failures++
+		}
+	}
+	return failures
+}
+
+// ParseTargets parses reports given as format=path
+func ParseTargets(values []string) ([]Target, error) {
+	var targets []Target
+	for _, value := range values {
+		parts := strings.SplitN(value, "=", 2)
+		if len(parts) != 2 || parts[1] == "" {
+			return nil, errors.Errorf("Invalid report %s, must be in the form junit=path or markdown=path", value)
+		}
+
+		format := strings.ToLower(parts[0])
+		if format != FormatJUnit && format != FormatMarkdown {
+			return nil, errors.Errorf("Unknown report format %s, must be one of [%s, %s]", parts[0], FormatJUnit, FormatMarkdown)
+		}
+
+		targets = append(targets, Target{Format: format, Path: parts[1]})
+	}
+	return targets, nil
+}
+
+// Write writes the suite to every target
+func Write(targets []Target, suite Suite) error {
+	for _, target := range targets {
+		if err := target.write(suite); err != nil {
+			return errors.Wrapf(err, "Could not write %s report to %s", target.Format, target.Path)
+		}
+	}
+	return nil
+}
+
+func (t Target) write(suite Suite) error {
+	if dir := filepath.Dir(t.Path); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+
+	file, err := os.Create(t.Path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if t.Format == FormatJUnit {
+		return WriteJUnit(file, suite)
+	}
+	return WriteMarkdown(file, suite)
+}
+
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Timestamp string          `xml:"timestamp,attr"`
+	Cases     []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	ClassName string        `xml:"classname,attr"`
+	Name      string        `xml:"name,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message  string `xml:"message,attr"`
+	Contents string `xml:",chardata"`
+}
+
+// WriteJUnit writes the suite as JUnit XML. Warnings are written to system-out.
+func WriteJUnit(w io.Writer, suite Suite) error {
+	junitSuite := junitTestSuite{
+		Name:      suite.Name,
+		Tests:     len(suite.Cases),
+		Failures:  suite.Failures(),
+		Timestamp: suite.Time.UTC().Format("2006-01-02T15:04:05"),
+	}
+
+	for _, c := range suite.Cases {
+		testCase := junitTestCase{
+			ClassName: c.ClassName,
+			Name:      c.Name,
+			SystemOut: strings.Join(c.Warnings, "\n"),
+		}
+		if c.Failed() {
+			testCase.Failure = &junitFailure{
+				Message:  strings.SplitN(c.Failure, "\n", 2)[0],
+				Contents: c.Failure,
+			}
+		}
+		junitSuite.Cases = append(junitSuite.Cases, testCase)
+	}
+
+	data, err := xml.MarshalIndent(junitTestSuites{Suites: []junitTestSuite{junitSuite}}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%s\n", data)
+	return err
+}
+
+// WriteMarkdown writes the suite as a markdown table followed by the failures and warnings
+func WriteMarkdown(w io.Writer, suite Suite) error {
+	var b strings.Builder
+
+	status := "✅"
+	if suite.Failures() > 0 {
+		status = "❌"
+	}
+	fmt.Fprintf(&b, "### %s %s\n\n", status, suite.Name)
+	fmt.Fprintf(&b, "%d of %d succeeded\n\n", len(suite.Cases)-suite.Failures(), len(suite.Cases))
+
+	b.WriteString("| Status | Group | Name | Message |\n")
+	b.WriteString("|--------|-------|------|---------|\n")
+	for _, c := range suite.Cases {
+		caseStatus := "✅"
+		message := ""
+		if c.Failed() {
+			caseStatus = "❌"
+			message = strings.SplitN(c.Failure, "\n", 2)[0]
+		} else if len(c.Warnings) > 0 {
+			caseStatus = "⚠️"
+			message = fmt.Sprintf("%d warning(s)", len(c.Warnings))
+		}
+		fmt.Fprintf(&b, "| %s | %s | %s | %s |\n", caseStatus, escapeMarkdown(c.ClassName), escapeMarkdown(c.Name), escapeMarkdown(message))
+	}
+
+	for _, c := range suite.Cases {
+		if !c.Failed() && len(c.Warnings) == 0 {
+			continue
+		}
+
+		fmt.Fprintf(&b, "\n#### %s %s\n", c.ClassName, c.Name)
+		if c.Failed() {
+			fmt.Fprintf(&b, "\n```\n%s\n```\n", c.Failure)
+		}
+		for _, warning := range c.Warnings {
+			fmt.Fprintf(&b, "\n- ⚠️ %s", strings.Replace(warning, "\n", " ", -1))
+		}
+		if len(c.Warnings) > 0 {
+			b.WriteString("\n")
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func escapeMarkdown(value string) string {
+	value = strings.Replace(value, "|", "\\|", -1)
+	return strings.Replace(value, "\n", " ", -1)
+}
@@ -0,0 +1,93 @@
+package report
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testSuite = Suite{
+	Name: "ao deploy",
+	Time: time.Date(2019, 11, 6, 10, 30, 0, 0, time.UTC),
+	Cases: []TestCase{
+		{ClassName: "utv.dev", Name: "crm", Warnings: []string{"Config is deprecated"}},
+		{ClassName: "utv.dev", Name: "erp", Failure: "Cluster is not reachable\nTry again later"},
+		{ClassName: "utv.dev", Name: "a|b"},
+	},
+}
+
+func Test_ParseTargets(t *testing.T) {
+	targets, err := ParseTargets([]string{"junit=build/ao.xml", "Markdown=ao.md"})
+	assert.NoError(t, err)
+	assert.Equal(t, []Target{{Format: FormatJUnit, Path: "build/ao.xml"}, {Format: FormatMarkdown, Path: "ao.md"}}, targets)
+
+	for _, invalid := range []string{"junit", "junit=", "html=report.html"} {
+		_, err := ParseTargets([]string{invalid})
+		assert.Error(t, err, invalid)
+	}
+}
+
+func Test_WriteJUnit(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, WriteJUnit(&buf, testSuite))
+
+	expected := `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites>
+  <testsuite name="ao deploy" tests="3" failures="1" timestamp="2019-11-06T10:30:00">
+    <testcase classname="utv.dev" name="crm">
+      <system-out>Config is deprecated</system-out>
+    </testcase>
+    <testcase classname="utv.dev" name="erp">
+      <failure message="Cluster is not reachable">Cluster is not reachable&#xA;Try again later</failure>
+    </testcase>
+    <testcase classname="utv.dev" name="a|b"></testcase>
+  </testsuite>
+</testsuites>
+`
+	assert.Equal(t, expected, buf.String())
+}
+
+func Test_WriteMarkdown(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, WriteMarkdown(&buf, testSuite))
+
+	expected := "### ❌ ao deploy\n\n" +
+		"2 of 3 succeeded\n\n" +
+		"| Status | Group | Name | Message |\n" +
+		"|--------|-------|------|---------|\n" +
+		"| ⚠️ | utv.dev | crm | 1 warning(s) |\n" +
+		"| ❌ | utv.dev | erp | Cluster is not reachable |\n" +
+		"| ✅ | utv.dev | a\\|b |  |\n" +
+		"\n#### utv.dev crm\n" +
+		"\n- ⚠️ Config is deprecated\n" +
+		"\n#### utv.dev erp\n" +
+		"\n```\nCluster is not reachable\nTry again later\n```\n"
+	assert.Equal(t, expected, buf.String())
+}
+
+func Test_Write(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ao_report")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	targets := []Target{
+		{Format: FormatJUnit, Path: filepath.Join(dir, "reports", "ao.xml")},
+		{Format: FormatMarkdown, Path: filepath.Join(dir, "ao.md")},
+	}
+	assert.NoError(t, Write(targets, testSuite))
+
+	junit, err := ioutil.ReadFile(targets[0].Path)
+	assert.NoError(t, err)
+	assert.Contains(t, string(junit), `<testsuite name="ao deploy"`)
+
+	markdown, err := ioutil.ReadFile(targets[1].Path)
+	assert.NoError(t, err)
+	assert.Contains(t, string(markdown), "### ❌ ao deploy")
+}
@@ -1,10 +1,12 @@
 package service
 
 import (
-	"errors"
 	"fmt"
 	"io"
+	"sort"
+	"strings"
 
+	"github.com/pkg/errors"
 	"github.com/skatteetaten/ao/pkg/auroraconfig"
 	"github.com/skatteetaten/ao/pkg/client"
 )
@@ -26,12 +28,7 @@ func GetApplications(apiClient client.AuroraConfigClient, pattern, version strin
 			return nil, errors.New("Deploy with version does only support one application")
 		}
 
-		fileName, err := filenames.Find(applications[0])
-		if err != nil {
-			return nil, err
-		}
-
-		err = updateVersion(apiClient, version, fileName, out)
+		err = UpdateVersion(apiClient, applications[0], version, out)
 		if err != nil {
 			return nil, err
 		}
@@ -40,6 +37,11 @@ func GetApplications(apiClient client.AuroraConfigClient, pattern, version strin
 	return applications, nil
 }
 
+// UpdateVersion sets version in the file of the given application
+func UpdateVersion(apiClient client.AuroraConfigClient, application, version string, out io.Writer) error {
+	return updateVersion(apiClient, version, application, out)
+}
+
 // SetValue updates single Aurora Config value
 func SetValue(apiClient client.AuroraConfigClient, name, path, value string) (string, error) {
 	fileNames, err := apiClient.GetFileNames()
@@ -69,6 +71,115 @@ func SetValue(apiClient client.AuroraConfigClient, name, path, value string) (st
 	return fileName, nil
 }
 
+// PatchFile applies all operations in patch to the file matching name in one request
+func PatchFile(apiClient client.AuroraConfigClient, name string, patch auroraconfig.JsonPatch) (string, error) {
+	if err := patch.Validate(); err != nil {
+		return "", err
+	}
+
+	fileName, err := findFileName(apiClient, name)
+	if err != nil {
+		return "", err
+	}
+
+	_, eTag, err := apiClient.GetAuroraConfigFile(fileName)
+	if err != nil {
+		return "", err
+	}
+
+	if err = apiClient.ApplyAuroraConfigFilePatch(fileName, patch, eTag); err != nil {
+		return "", err
+	}
+
+	return fileName, nil
+}
+
+// MergePatchFile applies a RFC 7386 merge patch to the file matching name. The merge patch is converted
+// to JSON patch operations against the current contents, and these are rejected by the server if the file
+// changes in the meantime. The applied operations are returned, and none are sent if nothing changes.
+func MergePatchFile(apiClient client.AuroraConfigClient, name string, mergePatch []byte) (string, auroraconfig.JsonPatch, error) {
+	fileName, err := findFileName(apiClient, name)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if !strings.HasSuffix(fileName, ".json") {
+		return "", nil, errors.Errorf("Merge patches are only supported for JSON files, %s is not", fileName)
+	}
+
+	file, eTag, err := apiClient.GetAuroraConfigFile(fileName)
+	if err != nil {
+		return "", nil, err
+	}
+
+	patch, err := auroraconfig.CreateMergePatchOps(file.Contents, mergePatch)
+	if err != nil {
+		return "", nil, errors.Wrap(err, fileName)
+	}
+
+	if len(patch) == 0 {
+		return fileName, patch, nil
+	}
+
+	if err = apiClient.ApplyAuroraConfigFilePatch(fileName, patch, eTag); err != nil {
+		return "", nil, err
+	}
+
+	return fileName, patch, nil
+}
+
+// PatchResult is the outcome of patching a single file
+type PatchResult struct {
+	FileName string
+	Err      error
+}
+
+// maxConcurrentPatches is the number of files patched at the same time by PatchFiles
+const maxConcurrentPatches = 4
+
+// PatchFiles applies patch to all files concurrently, sending one request per file. Concurrent changes to
+// a file are handled like for a single patch. The results are sorted by file name.
+func PatchFiles(apiClient client.AuroraConfigClient, fileNames []string, patch auroraconfig.JsonPatch) []PatchResult {
+	if err := patch.Validate(); err != nil {
+		var results []PatchResult
+		for _, fileName := range fileNames {
+			results = append(results, PatchResult{FileName: fileName, Err: err})
+		}
+		return results
+	}
+
+	resultChannel := make(chan PatchResult)
+	slots := make(chan struct{}, maxConcurrentPatches)
+	for _, fileName := range fileNames {
+		go func(fileName string) {
+			slots <- struct{}{}
+			err := apiClient.PatchAuroraConfigFileOps(fileName, patch)
+			<-slots
+			resultChannel <- PatchResult{FileName: fileName, Err: err}
+		}(fileName)
+	}
+
+	var results []PatchResult
+	for range fileNames {
+		results = append(results, <-resultChannel)
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].FileName < results[j].FileName
+	})
+
+	return results
+}
+
+func findFileName(apiClient client.AuroraConfigClient, name string) (string, error) {
+	fileNames, err := apiClient.GetFileNames()
+	if err != nil {
+		return "", err
+	}
+
+	return fileNames.Find(name)
+}
+
 func updateVersion(apiClient client.AuroraConfigClient, version, fileName string, out io.Writer) error {
 	path, value := "/version", version
 
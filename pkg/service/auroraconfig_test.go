@@ -1,9 +1,11 @@
 package service
 
 import (
+	"encoding/json"
 	"os"
 	"testing"
 
+	"github.com/skatteetaten/ao/pkg/auroraconfig"
 	"github.com/skatteetaten/ao/pkg/client"
 	"github.com/stretchr/testify/assert"
 )
@@ -47,3 +49,77 @@ func Test_getApplicationsWithExclusions(t *testing.T) {
 	assert.Contains(t, actualApplications, "test-qa/crm")
 	assert.Contains(t, actualApplications, "test-st/crm-2-GA")
 }
+
+func Test_SetValue(t *testing.T) {
+	apiClient := client.NewAuroraConfigClientMock(fileNames[:])
+	apiClient.On("PatchAuroraConfigFile", "dev/crm.json", auroraconfig.JsonPatchOp{OP: "add", Path: "/version", Value: "1.2.3"}).Once()
+
+	fileName, err := SetValue(apiClient, "dev/crm", "/version", "1.2.3")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "dev/crm.json", fileName)
+	apiClient.AssertExpectations(t)
+
+	_, err = SetValue(apiClient, "dev/crm", "version", "1.2.3")
+	assert.Error(t, err)
+}
+
+func Test_PatchFile(t *testing.T) {
+	apiClient := client.NewAuroraConfigClientMock(fileNames[:])
+	apiClient.Files = map[string]string{"dev/crm.json": `{}`}
+
+	patch := auroraconfig.JsonPatch{
+		{OP: "test", Path: "/version", Value: "1"},
+		{OP: "move", From: "/config/A", Path: "/config/B"},
+	}
+	apiClient.On("ApplyAuroraConfigFilePatch", "dev/crm.json", patch, "dev/crm.json").Once()
+
+	fileName, err := PatchFile(apiClient, "dev/crm", patch)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "dev/crm.json", fileName)
+	apiClient.AssertExpectations(t)
+
+	_, err = PatchFile(apiClient, "dev/crm", auroraconfig.JsonPatch{{OP: "merge", Path: "/a"}})
+	assert.Error(t, err)
+}
+
+func Test_MergePatchFile(t *testing.T) {
+	apiClient := client.NewAuroraConfigClientMock(fileNames[:])
+	apiClient.Files = map[string]string{"dev/crm.json": `{"replicas": 1, "pause": true}`}
+
+	expected := auroraconfig.JsonPatch{
+		{OP: "remove", Path: "/pause"},
+		{OP: "replace", Path: "/replicas", Value: json.Number("2")},
+	}
+	apiClient.On("ApplyAuroraConfigFilePatch", "dev/crm.json", expected, "dev/crm.json").Once()
+
+	fileName, patch, err := MergePatchFile(apiClient, "dev/crm", []byte(`{"replicas": 2, "pause": null}`))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "dev/crm.json", fileName)
+	assert.Equal(t, expected, patch)
+
+	_, patch, err = MergePatchFile(apiClient, "dev/crm", []byte(`{"replicas": 1}`))
+	assert.NoError(t, err)
+	assert.Empty(t, patch)
+	apiClient.AssertExpectations(t)
+}
+
+func Test_PatchFiles(t *testing.T) {
+	apiClient := client.NewAuroraConfigClientMock(fileNames[:])
+
+	patch := auroraconfig.JsonPatch{{OP: "remove", Path: "/pause"}}
+	conflict := &client.ConflictError{FileName: "dev/erp.json"}
+	apiClient.On("PatchAuroraConfigFileOps", "dev/erp.json", patch).Return(conflict).Once()
+	apiClient.On("PatchAuroraConfigFileOps", "dev/crm.json", patch).Return(nil).Once()
+
+	results := PatchFiles(apiClient, []string{"dev/erp.json", "dev/crm.json"}, patch)
+
+	assert.Equal(t, []PatchResult{{FileName: "dev/crm.json"}, {FileName: "dev/erp.json", Err: conflict}}, results)
+	apiClient.AssertExpectations(t)
+
+	results = PatchFiles(apiClient, []string{"dev/crm.json"}, auroraconfig.JsonPatch{{OP: "remove", Path: "pause"}})
+	assert.Len(t, results, 1)
+	assert.Error(t, results[0].Err)
+}
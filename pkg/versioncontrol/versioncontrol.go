@@ -82,6 +82,25 @@ func CollectAuroraConfigFilesInRepo(affiliation, gitRoot string) (*auroraconfig.
 	})
 }
 
+// ReadFileAtRevision returns the contents of a file at a git revision, e.g. HEAD. Exists is false if the
+// file is not in the revision.
+func ReadFileAtRevision(gitRoot, revision, fileName string) (contents string, exists bool, err error) {
+	if err := exec.Command("git", "-C", gitRoot, "rev-parse", "--verify", "--quiet", revision+"^{commit}").Run(); err != nil {
+		return "", false, errors.Errorf("Unknown git revision %s", revision)
+	}
+
+	object := revision + ":" + filepath.ToSlash(fileName)
+	if err := exec.Command("git", "-C", gitRoot, "cat-file", "-e", object).Run(); err != nil {
+		return "", false, nil
+	}
+
+	output, err := exec.Command("git", "-C", gitRoot, "show", object).Output()
+	if err != nil {
+		return "", false, errors.Wrapf(err, "Could not read %s at %s", fileName, revision)
+	}
+	return string(output), true, nil
+}
+
 func HasOneOfExtension(text string, items []string) bool {
 	for _, item := range items {
 		if ok := strings.HasSuffix(text, item); ok {
@@ -177,3 +177,26 @@ func TestCreateGitValidateHook(t *testing.T) {
 		})
 	}
 }
+
+func TestReadFileAtRevision(t *testing.T) {
+	repoSetup("")
+	os.Mkdir(REPO_PATH+"/test", 0755)
+	assert.NoError(t, ioutil.WriteFile(REPO_PATH+"/test/about.json", []byte(testFiles["test/about.json"]), 0644))
+
+	_, _, err := ReadFileAtRevision(REPO_PATH, "HEAD", "test/about.json")
+	assert.EqualError(t, err, "Unknown git revision HEAD")
+
+	commit := exec.Command("git", "-c", "user.name=ao", "-c", "user.email=ao@example.com", "commit", "-q", "-m", "Add about.json")
+	assert.NoError(t, exec.Command("git", "add", "-A").Run())
+	assert.NoError(t, commit.Run())
+	assert.NoError(t, ioutil.WriteFile(REPO_PATH+"/test/about.json", []byte(`{"cluster": "utv"}`), 0644))
+
+	contents, exists, err := ReadFileAtRevision(REPO_PATH, "HEAD", "test/about.json")
+	assert.NoError(t, err)
+	assert.True(t, exists)
+	assert.Equal(t, testFiles["test/about.json"], contents)
+
+	_, exists, err = ReadFileAtRevision(REPO_PATH, "HEAD", "test/reference.json")
+	assert.NoError(t, err)
+	assert.False(t, exists)
+}